@@ -3,10 +3,12 @@ package autoscaler
 import (
 	"fmt"
 
+	"golang.org/x/net/context"
+
 	"bytes"
 	"encoding/json"
-	"io/ioutil"
 	"net/http"
+	"sort"
 )
 
 // Client represents the behavior of App Autoscaler API client
@@ -17,11 +19,14 @@ type Client interface {
 	// Get the Service Binding given the Binding GUID
 	GetBinding(bindingGUID string) (*BindingResource, error)
 
-	// Update the Binding
+	// Update the Binding, enabled is only sent when true so that updating the limits leaves autoscaling as is
 	UpdateBinding(bindingGUID string, binding *Binding) (*BindingResource, error)
 
-	// Get the Scaling decisions for a Binding
-	GetScalingDecisions(bindingGUID string) ([]ScalingDecision, error)
+	// Update the Binding, always sending enabled so that autoscaling can be turned off
+	UpdateBindingEnabled(bindingGUID string, binding *Binding) (*BindingResource, error)
+
+	// Get the Scaling decisions for a Binding in chronological order, optionally narrowed down by a filter
+	GetScalingDecisions(bindingGUID string, filter *ScalingDecisionsFilter) ([]ScalingDecision, error)
 
 	// Get the Schedueled Limit changes for a Binding
 	GetScheduledLimitChanges(bindingGUID string) ([]ScheduledLimitChange, error)
@@ -34,13 +39,67 @@ type Client interface {
 
 	//Delete Scheduled Limit changes
 	DeleteScheduledLimitChange(bindingGUID string, changeGUID string) error
+
+	// Get the Rules for a Binding
+	GetRules(bindingGUID string) ([]Rule, error)
+
+	// Create a Rule for a Binding
+	CreateRule(bindingGUID string, rule *Rule) (*Rule, error)
+
+	// Update a Rule of a Binding
+	UpdateRule(bindingGUID string, ruleGUID string, rule *Rule) (*Rule, error)
+
+	// Delete a Rule of a Binding
+	DeleteRule(bindingGUID string, ruleGUID string) error
+
+	// Follow a link of a Binding, like "self", "events" or "scheduled_limit_changes", decoding the linked resource into out.
+	// When out is a pointer to a slice, every page of the linked list is decoded into it
+	Follow(binding *BindingResource, rel string, out interface{}) error
+
+	// Find the Binding of an application to the service instance, given the names of the org, space and application
+	FindBindingByAppName(org, space, app string) (*BindingResource, error)
+
+	// Find the Binding of an application to the service instance, given the application GUID
+	FindBindingByAppGUID(appGUID string) (*BindingResource, error)
+
+	ContextClient
+}
+
+// ContextClient represents the behavior of App Autoscaler API client where every call is bound to a context,
+// allowing calls to be cancelled or to carry a deadline
+type ContextClient interface {
+	GetServiceBindingsContext(ctx context.Context) (*ServiceInstances, error)
+	GetBindingContext(ctx context.Context, bindingGUID string) (*BindingResource, error)
+	UpdateBindingContext(ctx context.Context, bindingGUID string, binding *Binding) (*BindingResource, error)
+	UpdateBindingEnabledContext(ctx context.Context, bindingGUID string, binding *Binding) (*BindingResource, error)
+	GetScalingDecisionsContext(ctx context.Context, bindingGUID string, filter *ScalingDecisionsFilter) ([]ScalingDecision, error)
+	GetScheduledLimitChangesContext(ctx context.Context, bindingGUID string) ([]ScheduledLimitChange, error)
+	CreateScheduledLimitChangeContext(ctx context.Context, bindingGUID string, scheduledLimitChange *ScheduledLimitChange) (*ScheduledLimitChange, error)
+	UpdateScheduledLimitChangeContext(ctx context.Context, bindingGUID string, changeGUID string, scheduledLimitChange *ScheduledLimitChange) (*ScheduledLimitChange, error)
+	DeleteScheduledLimitChangeContext(ctx context.Context, bindingGUID string, changeGUID string) error
+	GetRulesContext(ctx context.Context, bindingGUID string) ([]Rule, error)
+	CreateRuleContext(ctx context.Context, bindingGUID string, rule *Rule) (*Rule, error)
+	UpdateRuleContext(ctx context.Context, bindingGUID string, ruleGUID string, rule *Rule) (*Rule, error)
+	DeleteRuleContext(ctx context.Context, bindingGUID string, ruleGUID string) error
+	FollowContext(ctx context.Context, binding *BindingResource, rel string, out interface{}) error
+	FindBindingByAppNameContext(ctx context.Context, org, space, app string) (*BindingResource, error)
+	FindBindingByAppGUIDContext(ctx context.Context, appGUID string) (*BindingResource, error)
 }
 
 // Config holds the configuration for autoscaler settings
 type Config struct {
-	CFConfig         *CFConfig
+	CFConfig *CFConfig
+	// AutoscalerAPIUrl is discovered through Cloud Controller if not set, from the dashboard url of the service
+	// instance, else from the system domain of Cloud Controller
 	AutoscalerAPIUrl string
 	InstanceGUID     string
+	// RetryPolicy for calls failing transiently, calls are made exactly once if not set
+	RetryPolicy *RetryPolicy
+	// PageSize is the number of resources asked for in every page of a list, the API default is used if not set
+	PageSize int
+	// StrictValidation makes the client validate bindings, rules and scheduled limit changes before sending them,
+	// failing with a ValidationError instead of calling the API
+	StrictValidation bool
 }
 
 // DefaultClient is the default implementation of Autoscaler Client
@@ -51,61 +110,70 @@ type DefaultClient struct {
 
 // NewClient is the helper for creating a new Autoscaler Client
 func NewClient(autoscalerConfig *Config) (Client, error) {
+	return NewClientWithContext(context.Background(), autoscalerConfig)
+}
+
+// NewClientWithContext creates a new Autoscaler Client, using the context for the calls made to authenticate
+func NewClientWithContext(ctx context.Context, autoscalerConfig *Config) (Client, error) {
 	uaaConfig := autoscalerConfig.CFConfig
-	oauthWrapper, err := NewUAAClient(uaaConfig)
+	oauthWrapper, err := NewUAAClientWithContext(ctx, uaaConfig)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return &DefaultClient{
+	client := &DefaultClient{
 		httpClient: oauthWrapper,
 		config:     autoscalerConfig,
-	}, nil
+	}
+	if autoscalerConfig.AutoscalerAPIUrl == "" {
+		apiURL, err := client.discoverAutoscalerAPIUrl(ctx, autoscalerConfig.InstanceGUID)
+		if err != nil {
+			return nil, err
+		}
+		discovered := *autoscalerConfig
+		discovered.AutoscalerAPIUrl = apiURL
+		client.config = &discovered
+	}
+	return client, nil
 }
 
 // GetServiceBindings ...
 func (client *DefaultClient) GetServiceBindings() (*ServiceInstances, error) {
+	return client.GetServiceBindingsContext(context.Background())
+}
+
+// GetServiceBindingsContext ...
+func (client *DefaultClient) GetServiceBindingsContext(ctx context.Context) (*ServiceInstances, error) {
 	serviceBindingsURL := fmt.Sprintf("%s/instances/%s/bindings", client.config.AutoscalerAPIUrl, client.config.InstanceGUID)
-	request, err := client.httpClient.NewRequest("GET", serviceBindingsURL, nil)
-	if err != nil {
-		return nil, err
-	}
-	resp, err := client.httpClient.Do(request)
-	if err != nil {
-		return nil, err
-	}
 
-	if resp.StatusCode != 200 {
-		body, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad Response: %s", body)
-	}
 	var serviceInstances ServiceInstances
-
-	decoder := json.NewDecoder(resp.Body)
-	if err = decoder.Decode(&serviceInstances); err != nil {
+	if err := client.ListAll(ctx, serviceBindingsURL, client.listOptions(), &serviceInstances.BindingResources); err != nil {
 		return nil, err
 	}
-
 	return &serviceInstances, nil
-
 }
 
 //GetBinding ...
 func (client *DefaultClient) GetBinding(bindingGUID string) (*BindingResource, error) {
+	return client.GetBindingContext(context.Background(), bindingGUID)
+}
+
+// GetBindingContext ...
+func (client *DefaultClient) GetBindingContext(ctx context.Context, bindingGUID string) (*BindingResource, error) {
 	bindingURL := fmt.Sprintf("%s/bindings/%s", client.config.AutoscalerAPIUrl, bindingGUID)
-	request, err := client.httpClient.NewRequest("GET", bindingURL, nil)
+	request, err := client.httpClient.NewRequestWithContext(ctx, "GET", bindingURL, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := client.httpClient.Do(request)
+	resp, err := client.do(request)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode != 200 {
-		body, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad Response: %s", body)
+		return nil, newAPIError(resp)
 	}
 	var binding BindingResource
 
@@ -118,25 +186,54 @@ func (client *DefaultClient) GetBinding(bindingGUID string) (*BindingResource, e
 
 //UpdateBinding ...
 func (client *DefaultClient) UpdateBinding(bindingGUID string, binding *Binding) (*BindingResource, error) {
+	return client.UpdateBindingContext(context.Background(), bindingGUID, binding)
+}
+
+// UpdateBindingContext ...
+func (client *DefaultClient) UpdateBindingContext(ctx context.Context, bindingGUID string, binding *Binding) (*BindingResource, error) {
+	return client.updateBinding(ctx, bindingGUID, binding, binding)
+}
+
+// UpdateBindingEnabled ...
+func (client *DefaultClient) UpdateBindingEnabled(bindingGUID string, binding *Binding) (*BindingResource, error) {
+	return client.UpdateBindingEnabledContext(context.Background(), bindingGUID, binding)
+}
+
+// UpdateBindingEnabledContext ...
+func (client *DefaultClient) UpdateBindingEnabledContext(ctx context.Context, bindingGUID string, binding *Binding) (*BindingResource, error) {
+	return client.updateBinding(ctx, bindingGUID, binding, &bindingWithEnabled{Binding: binding, Enabled: binding.Enabled})
+}
+
+// bindingWithEnabled is the body of a binding update sending enabled even when false
+type bindingWithEnabled struct {
+	*Binding
+	Enabled bool `json:"enabled"`
+}
+
+// updateBinding sends the body of the update of the binding, once the binding is validated
+func (client *DefaultClient) updateBinding(ctx context.Context, bindingGUID string, binding *Binding, update interface{}) (*BindingResource, error) {
 	bindingURL := fmt.Sprintf("%s/bindings/%s", client.config.AutoscalerAPIUrl, bindingGUID)
 
-	body, err := json.Marshal(binding)
+	if err := client.validate(binding); err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(update)
 	if err != nil {
 		return nil, err
 	}
-	request, err := client.httpClient.NewRequest("PUT", bindingURL, bytes.NewBuffer(body))
+	request, err := client.httpClient.NewRequestWithContext(ctx, "PUT", bindingURL, bytes.NewBuffer(body))
 
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := client.httpClient.Do(request)
+	resp, err := client.do(request)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode != 200 {
-		body, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad Response: %s", body)
+		return nil, newAPIError(resp)
 	}
 	var bindingUpdated BindingResource
 
@@ -148,60 +245,87 @@ func (client *DefaultClient) UpdateBinding(bindingGUID string, binding *Binding)
 }
 
 // GetScalingDecisions ...
-func (client *DefaultClient) GetScalingDecisions(bindingGUID string) ([]ScalingDecision, error) {
-	return nil, fmt.Errorf("%s", "Not implemented...")
+func (client *DefaultClient) GetScalingDecisions(bindingGUID string, filter *ScalingDecisionsFilter) ([]ScalingDecision, error) {
+	return client.GetScalingDecisionsContext(context.Background(), bindingGUID, filter)
 }
 
-// GetScheduledLimitChanges ...
-func (client *DefaultClient) GetScheduledLimitChanges(bindingGUID string) ([]ScheduledLimitChange, error) {
-	schedulesForBindingURL := fmt.Sprintf("%s/bindings/%s/scheduled_limit_changes", client.config.AutoscalerAPIUrl, bindingGUID)
-
-	request, err := client.httpClient.NewRequest("GET", schedulesForBindingURL, nil)
-	if err != nil {
-		return nil, err
+// GetScalingDecisionsContext ...
+func (client *DefaultClient) GetScalingDecisionsContext(ctx context.Context, bindingGUID string, filter *ScalingDecisionsFilter) ([]ScalingDecision, error) {
+	if filter == nil {
+		filter = &ScalingDecisionsFilter{}
 	}
-
-	resp, err := client.httpClient.Do(request)
+	eventsURL := fmt.Sprintf("%s/bindings/%s/scaling_events", client.config.AutoscalerAPIUrl, bindingGUID)
+
+	var decisions []ScalingDecision
+	err := client.ListPages(ctx, eventsURL, client.listOptions(), func(page *Page) error {
+		for _, resource := range page.Resources {
+			var decision ScalingDecision
+			if err := json.Unmarshal(resource, &decision); err != nil {
+				return err
+			}
+			if filter.matches(decision) {
+				decisions = append(decisions, decision)
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	if resp.StatusCode != 200 {
-		body, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad Response: %s", body)
+
+	sort.Stable(byCreatedAt(decisions))
+
+	if filter.MaxResults > 0 && len(decisions) > filter.MaxResults {
+		decisions = decisions[len(decisions)-filter.MaxResults:]
 	}
+	return decisions, nil
+}
 
-	var changesResource ScheduledLimitChangesResource
+// GetScheduledLimitChanges ...
+func (client *DefaultClient) GetScheduledLimitChanges(bindingGUID string) ([]ScheduledLimitChange, error) {
+	return client.GetScheduledLimitChangesContext(context.Background(), bindingGUID)
+}
 
-	decoder := json.NewDecoder(resp.Body)
-	if err = decoder.Decode(&changesResource); err != nil {
+// GetScheduledLimitChangesContext ...
+func (client *DefaultClient) GetScheduledLimitChangesContext(ctx context.Context, bindingGUID string) ([]ScheduledLimitChange, error) {
+	schedulesForBindingURL := fmt.Sprintf("%s/bindings/%s/scheduled_limit_changes", client.config.AutoscalerAPIUrl, bindingGUID)
+
+	var scheduledLimitChanges []ScheduledLimitChange
+	if err := client.ListAll(ctx, schedulesForBindingURL, client.listOptions(), &scheduledLimitChanges); err != nil {
 		return nil, err
 	}
-	return changesResource.ScheduledLimitChanges, nil
+	return scheduledLimitChanges, nil
 }
 
 //CreateScheduledLimitChange ...
 func (client *DefaultClient) CreateScheduledLimitChange(bindingGUID string, scheduledLimitChange *ScheduledLimitChange) (*ScheduledLimitChange, error) {
+	return client.CreateScheduledLimitChangeContext(context.Background(), bindingGUID, scheduledLimitChange)
+}
+
+// CreateScheduledLimitChangeContext ...
+func (client *DefaultClient) CreateScheduledLimitChangeContext(ctx context.Context, bindingGUID string, scheduledLimitChange *ScheduledLimitChange) (*ScheduledLimitChange, error) {
 	schedulesForBindingURL := fmt.Sprintf("%s/bindings/%s/scheduled_limit_changes", client.config.AutoscalerAPIUrl, bindingGUID)
 
+	if err := client.validate(scheduledLimitChange); err != nil {
+		return nil, err
+	}
 	body, err := json.Marshal(scheduledLimitChange)
 	if err != nil {
 		return nil, err
 	}
-	fmt.Printf("%s", string(body))
-
-	request, err := client.httpClient.NewRequest("POST", schedulesForBindingURL, bytes.NewBuffer(body))
+	request, err := client.httpClient.NewRequestWithContext(ctx, "POST", schedulesForBindingURL, bytes.NewBuffer(body))
 
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := client.httpClient.Do(request)
+	resp, err := client.do(request)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusCreated {
-		body, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Status Code: %d, Body:%s", resp.StatusCode, body)
+		return nil, newAPIError(resp)
 	}
 	var scheduledLimitChangeUpdated ScheduledLimitChange
 
@@ -214,26 +338,34 @@ func (client *DefaultClient) CreateScheduledLimitChange(bindingGUID string, sche
 
 // UpdateScheduledLimitChange ...
 func (client *DefaultClient) UpdateScheduledLimitChange(bindingGUID string, changeGUID string, scheduledLimitChange *ScheduledLimitChange) (*ScheduledLimitChange, error) {
+	return client.UpdateScheduledLimitChangeContext(context.Background(), bindingGUID, changeGUID, scheduledLimitChange)
+}
+
+// UpdateScheduledLimitChangeContext ...
+func (client *DefaultClient) UpdateScheduledLimitChangeContext(ctx context.Context, bindingGUID string, changeGUID string, scheduledLimitChange *ScheduledLimitChange) (*ScheduledLimitChange, error) {
 	schedulesForBindingURL := fmt.Sprintf("%s/bindings/%s/scheduled_limit_changes/%s", client.config.AutoscalerAPIUrl, bindingGUID, changeGUID)
 
+	if err := client.validate(scheduledLimitChange); err != nil {
+		return nil, err
+	}
 	body, err := json.Marshal(scheduledLimitChange)
 	if err != nil {
 		return nil, err
 	}
 
-	request, err := client.httpClient.NewRequest("PUT", schedulesForBindingURL, bytes.NewBuffer(body))
+	request, err := client.httpClient.NewRequestWithContext(ctx, "PUT", schedulesForBindingURL, bytes.NewBuffer(body))
 
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := client.httpClient.Do(request)
+	resp, err := client.do(request)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode != 200 {
-		body, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad Response: %s", body)
+		return nil, newAPIError(resp)
 	}
 	var scheduledLimitChangeUpdated ScheduledLimitChange
 
@@ -246,23 +378,178 @@ func (client *DefaultClient) UpdateScheduledLimitChange(bindingGUID string, chan
 
 // DeleteScheduledLimitChange ...
 func (client *DefaultClient) DeleteScheduledLimitChange(bindingGUID string, changeGUID string) error {
+	return client.DeleteScheduledLimitChangeContext(context.Background(), bindingGUID, changeGUID)
+}
+
+// DeleteScheduledLimitChangeContext ...
+func (client *DefaultClient) DeleteScheduledLimitChangeContext(ctx context.Context, bindingGUID string, changeGUID string) error {
 	schedulesForBindingURL := fmt.Sprintf("%s/bindings/%s/scheduled_limit_changes/%s", client.config.AutoscalerAPIUrl, bindingGUID, changeGUID)
 
-	request, err := client.httpClient.NewRequest("DELETE", schedulesForBindingURL, nil)
+	request, err := client.httpClient.NewRequestWithContext(ctx, "DELETE", schedulesForBindingURL, nil)
+
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.do(request)
+
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return newAPIError(resp)
+	}
+
+	return nil
+}
+
+// GetRules ...
+func (client *DefaultClient) GetRules(bindingGUID string) ([]Rule, error) {
+	return client.GetRulesContext(context.Background(), bindingGUID)
+}
+
+// GetRulesContext ...
+func (client *DefaultClient) GetRulesContext(ctx context.Context, bindingGUID string) ([]Rule, error) {
+	rulesForBindingURL := fmt.Sprintf("%s/bindings/%s/rules", client.config.AutoscalerAPIUrl, bindingGUID)
+
+	var rules []Rule
+	if err := client.ListAll(ctx, rulesForBindingURL, client.listOptions(), &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// CreateRule ...
+func (client *DefaultClient) CreateRule(bindingGUID string, rule *Rule) (*Rule, error) {
+	return client.CreateRuleContext(context.Background(), bindingGUID, rule)
+}
+
+// CreateRuleContext ...
+func (client *DefaultClient) CreateRuleContext(ctx context.Context, bindingGUID string, rule *Rule) (*Rule, error) {
+	rulesForBindingURL := fmt.Sprintf("%s/bindings/%s/rules", client.config.AutoscalerAPIUrl, bindingGUID)
+
+	if err := client.validate(rule); err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(rule)
+	if err != nil {
+		return nil, err
+	}
+	request, err := client.httpClient.NewRequestWithContext(ctx, "POST", rulesForBindingURL, bytes.NewBuffer(body))
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return nil, newAPIError(resp)
+	}
+	var ruleCreated Rule
+
+	decoder := json.NewDecoder(resp.Body)
+	if err = decoder.Decode(&ruleCreated); err != nil {
+		return nil, err
+	}
+	return &ruleCreated, nil
+}
+
+// UpdateRule ...
+func (client *DefaultClient) UpdateRule(bindingGUID string, ruleGUID string, rule *Rule) (*Rule, error) {
+	return client.UpdateRuleContext(context.Background(), bindingGUID, ruleGUID, rule)
+}
+
+// UpdateRuleContext ...
+func (client *DefaultClient) UpdateRuleContext(ctx context.Context, bindingGUID string, ruleGUID string, rule *Rule) (*Rule, error) {
+	ruleURL := fmt.Sprintf("%s/bindings/%s/rules/%s", client.config.AutoscalerAPIUrl, bindingGUID, ruleGUID)
+
+	if err := client.validate(rule); err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := client.httpClient.NewRequestWithContext(ctx, "PUT", ruleURL, bytes.NewBuffer(body))
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, newAPIError(resp)
+	}
+	var ruleUpdated Rule
+
+	decoder := json.NewDecoder(resp.Body)
+	if err = decoder.Decode(&ruleUpdated); err != nil {
+		return nil, err
+	}
+	return &ruleUpdated, nil
+}
+
+// DeleteRule ...
+func (client *DefaultClient) DeleteRule(bindingGUID string, ruleGUID string) error {
+	return client.DeleteRuleContext(context.Background(), bindingGUID, ruleGUID)
+}
+
+// DeleteRuleContext ...
+func (client *DefaultClient) DeleteRuleContext(ctx context.Context, bindingGUID string, ruleGUID string) error {
+	ruleURL := fmt.Sprintf("%s/bindings/%s/rules/%s", client.config.AutoscalerAPIUrl, bindingGUID, ruleGUID)
+
+	request, err := client.httpClient.NewRequestWithContext(ctx, "DELETE", ruleURL, nil)
 
 	if err != nil {
 		return err
 	}
 
-	resp, err := client.httpClient.Do(request)
+	resp, err := client.do(request)
 
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode != 200 {
-		body, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("Bad Response: %s", body)
+		return newAPIError(resp)
 	}
 
 	return nil
 }
+
+func (filter *ScalingDecisionsFilter) matches(decision ScalingDecision) bool {
+	if filter.Since.IsZero() && filter.Until.IsZero() {
+		return true
+	}
+	if decision.CreatedAt == nil {
+		return false
+	}
+	if !filter.Since.IsZero() && decision.CreatedAt.Before(filter.Since) {
+		return false
+	}
+	if !filter.Until.IsZero() && decision.CreatedAt.After(filter.Until) {
+		return false
+	}
+	return true
+}
+
+type byCreatedAt []ScalingDecision
+
+func (decisions byCreatedAt) Len() int      { return len(decisions) }
+func (decisions byCreatedAt) Swap(i, j int) { decisions[i], decisions[j] = decisions[j], decisions[i] }
+func (decisions byCreatedAt) Less(i, j int) bool {
+	if decisions[i].CreatedAt == nil || decisions[j].CreatedAt == nil {
+		return decisions[i].CreatedAt == nil && decisions[j].CreatedAt != nil
+	}
+	return decisions[i].CreatedAt.Before(*decisions[j].CreatedAt)
+}
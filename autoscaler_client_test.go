@@ -11,6 +11,7 @@ import (
 
 	"github.com/bijukunjummen/app-autoscaler-client"
 	"github.com/onsi/gomega/ghttp"
+	"golang.org/x/net/context"
 )
 
 var _ = Describe("Behavior of Auto Scaler", func() {
@@ -227,6 +228,57 @@ var _ = Describe("Behavior of Auto Scaler", func() {
 
 			Ω(bindingResource.AppName).Should(Equal("sample-spring-cloud-svc-ci"))
 		})
+
+		It("Should leave enabled out of an update of the limits only", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/api/bindings/mybinding"),
+					verifyEnabledSent(nil),
+					ghttp.RespondWith(http.StatusOK, sampleBindingJson),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/api/bindings/mybinding"),
+					verifyEnabledSent(false),
+					ghttp.RespondWith(http.StatusOK, sampleBindingJson),
+				),
+			)
+			client, err := autoscaler.NewClient(config)
+			Ω(err).Should(BeNil())
+
+			_, err = client.UpdateBinding("mybinding", &autoscaler.Binding{MinInstances: 2, MaxInstances: 5})
+			Ω(err).Should(BeNil())
+			_, err = client.UpdateBindingEnabled("mybinding", &autoscaler.Binding{MinInstances: 2, MaxInstances: 5})
+
+			Ω(err).Should(BeNil())
+		})
+
+		It("Should be able to Get Details of a binding bound to a context", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/bindings/mybinding"),
+					ghttp.RespondWith(http.StatusOK, sampleBindingJson),
+				),
+			)
+			client, err := autoscaler.NewClientWithContext(context.Background(), config)
+			Ω(err).Should(BeNil())
+
+			binding, err := client.GetBindingContext(context.Background(), "mybinding")
+			Ω(err).Should(BeNil())
+
+			Ω(binding.AppName).Should(Equal("sample-spring-cloud-svc-ci"))
+		})
+
+		It("Should not make a call bound to a cancelled context", func() {
+			client, err := autoscaler.NewClient(config)
+			Ω(err).Should(BeNil())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, err = client.GetBindingContext(ctx, "mybinding")
+			Ω(err).ShouldNot(BeNil())
+			Ω(len(server.ReceivedRequests())).Should(Equal(2))
+		})
 	})
 
 	scheduledLimitChangesResource :=
@@ -296,7 +348,7 @@ var _ = Describe("Behavior of Auto Scaler", func() {
 		Ω(*change1.UpdatedAt).Should(Equal(auditDate))
 		Ω(*change1.ExecutesAt).Should(Equal(eDate))
 		Ω(change1.ServiceBindingGUID).Should(Equal("540f43bc-b9cc-4126-97a4-a56b64052da4"))
-		Ω(change1.Recurrence).Should(Equal(20))
+		Ω(change1.Recurrence).Should(Equal(autoscaler.Recurrence(20)))
 		Ω(change1.Enabled).Should(Equal(true))
 	})
 
@@ -317,7 +369,7 @@ var _ = Describe("Behavior of Auto Scaler", func() {
 		Ω(err).Should(BeNil())
 		scheduleUpdated, _ := client.UpdateScheduledLimitChange("mybinding", "changeid", &scheduledLimitChangeObj)
 		Ω(scheduleUpdated.Enabled).Should(BeTrue())
-		Ω(scheduleUpdated.Recurrence).Should(Equal(20))
+		Ω(scheduleUpdated.Recurrence).Should(Equal(autoscaler.Recurrence(20)))
 		Ω(scheduleUpdated.ServiceBindingGUID).Should(Equal("540f43bc-b9cc-4126-97a4-a56b64052da4"))
 		Ω(scheduleUpdated.MinInstances).Should(Equal(2))
 		Ω(scheduleUpdated.MaxInstances).Should(Equal(3))
@@ -341,7 +393,7 @@ var _ = Describe("Behavior of Auto Scaler", func() {
 		Ω(err).Should(BeNil())
 		scheduleUpdated, _ := client.CreateScheduledLimitChange("mybinding", &scheduledLimitChangeObj)
 		Ω(scheduleUpdated.Enabled).Should(BeTrue())
-		Ω(scheduleUpdated.Recurrence).Should(Equal(20))
+		Ω(scheduleUpdated.Recurrence).Should(Equal(autoscaler.Recurrence(20)))
 		Ω(scheduleUpdated.ServiceBindingGUID).Should(Equal("540f43bc-b9cc-4126-97a4-a56b64052da4"))
 		Ω(scheduleUpdated.MinInstances).Should(Equal(2))
 		Ω(scheduleUpdated.MaxInstances).Should(Equal(3))
@@ -366,4 +418,244 @@ var _ = Describe("Behavior of Auto Scaler", func() {
 		err = client.DeleteScheduledLimitChange("mybinding", "changeid")
 		Ω(err).Should(BeNil())
 	})
+
+	rulesResource := `
+	{"resources": [
+	  {
+	    "guid": "d5524ca3-5eda-43ea-688c-f5685d70b64e",
+	    "created_at": "2016-12-30T00:48:36Z",
+	    "updated_at": "2016-12-30T00:48:36Z",
+	    "type": "http_latency",
+	    "enabled": false,
+	    "min_threshold": 100,
+	    "max_threshold": 900,
+	    "service_binding_guid": "mybinding",
+	    "sub_type": "avg_95th"
+	  },
+	  {
+	    "guid": "d53b1ca4-9e01-47c2-7b35-8805cd66f351",
+	    "created_at": "2016-12-20T13:53:23Z",
+	    "updated_at": "2016-12-30T00:48:36Z",
+	    "type": "cpu",
+	    "enabled": true,
+	    "min_threshold": 20,
+	    "max_threshold": 80,
+	    "service_binding_guid": "mybinding"
+	  }
+	]}`
+
+	rule := `
+	{
+	  "guid": "d53b1ca4-9e01-47c2-7b35-8805cd66f351",
+	  "created_at": "2016-12-20T13:53:23Z",
+	  "updated_at": "2016-12-30T00:48:36Z",
+	  "type": "cpu",
+	  "enabled": true,
+	  "min_threshold": 20,
+	  "max_threshold": 80,
+	  "service_binding_guid": "mybinding"
+	}`
+
+	It("Should be able to Retrieve Rules for a binding given a Binding Id", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/bindings/mybinding/rules"),
+				ghttp.VerifyHeader(http.Header{
+					"Authorization": []string{"Bearer test-token"},
+				}),
+				ghttp.RespondWith(http.StatusOK, rulesResource),
+			),
+		)
+		client, _ := autoscaler.NewClient(config)
+		rules, err := client.GetRules("mybinding")
+		Ω(err).Should(BeNil())
+		Ω(len(rules)).Should(Equal(2))
+		Ω(rules[0].Type).Should(Equal("http_latency"))
+		Ω(rules[0].SubType).Should(Equal("avg_95th"))
+		Ω(rules[0].MinThreshold).Should(Equal(100))
+		Ω(rules[0].MaxThreshold).Should(Equal(900))
+		Ω(rules[1].GUID).Should(Equal("d53b1ca4-9e01-47c2-7b35-8805cd66f351"))
+	})
+
+	It("Should be able to create a rule", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/api/bindings/mybinding/rules"),
+				ghttp.VerifyHeader(http.Header{
+					"Authorization": []string{"Bearer test-token"},
+				}),
+				func(w http.ResponseWriter, req *http.Request) {
+					var ruleSent autoscaler.Rule
+					Ω(json.NewDecoder(req.Body).Decode(&ruleSent)).Should(Succeed())
+					Ω(ruleSent.Type).Should(Equal("cpu"))
+					Ω(ruleSent.MaxThreshold).Should(Equal(80))
+				},
+				ghttp.RespondWith(http.StatusCreated, rule),
+			),
+		)
+
+		client, _ := autoscaler.NewClient(config)
+		ruleCreated, err := client.CreateRule("mybinding", &autoscaler.Rule{
+			Type:         "cpu",
+			Enabled:      true,
+			MinThreshold: 20,
+			MaxThreshold: 80,
+		})
+		Ω(err).Should(BeNil())
+		Ω(ruleCreated.GUID).Should(Equal("d53b1ca4-9e01-47c2-7b35-8805cd66f351"))
+		Ω(ruleCreated.ServiceBindingGUID).Should(Equal("mybinding"))
+	})
+
+	It("Should be able to update a rule", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", "/api/bindings/mybinding/rules/ruleid"),
+				ghttp.VerifyHeader(http.Header{
+					"Authorization": []string{"Bearer test-token"},
+				}),
+				ghttp.RespondWith(http.StatusOK, rule),
+			),
+		)
+
+		client, _ := autoscaler.NewClient(config)
+		var ruleObj autoscaler.Rule
+		err := json.Unmarshal([]byte(rule), &ruleObj)
+		Ω(err).Should(BeNil())
+		ruleUpdated, err := client.UpdateRule("mybinding", "ruleid", &ruleObj)
+		Ω(err).Should(BeNil())
+		Ω(ruleUpdated.Type).Should(Equal("cpu"))
+		Ω(ruleUpdated.Enabled).Should(BeTrue())
+		Ω(ruleUpdated.MinThreshold).Should(Equal(20))
+		Ω(ruleUpdated.MaxThreshold).Should(Equal(80))
+	})
+
+	It("Should be able to delete a rule", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/api/bindings/mybinding/rules/ruleid"),
+				ghttp.VerifyHeader(http.Header{
+					"Authorization": []string{"Bearer test-token"},
+				}),
+				ghttp.RespondWith(http.StatusOK, nil),
+			),
+		)
+
+		client, _ := autoscaler.NewClient(config)
+		err := client.DeleteRule("mybinding", "ruleid")
+		Ω(err).Should(BeNil())
+	})
+
+	scalingEventsPage1 := `
+	{
+	  "resources": [
+	    {
+	      "guid": "event-3",
+	      "service_binding_guid": "mybinding",
+	      "scaling_factor": 1,
+	      "description": "Scaled up from 2 to 3 instances.",
+	      "created_at": "2016-12-30T03:05:00Z"
+	    },
+	    {
+	      "guid": "event-1",
+	      "service_binding_guid": "mybinding",
+	      "scaling_factor": 0,
+	      "description": "Cannot scale: at min limit of 2 instances.",
+	      "created_at": "2016-12-29T07:26:31Z"
+	    }
+	  ],
+	  "pagination": {
+	    "total_results": 3,
+	    "total_pages": 2,
+	    "next": {"href": "/api/bindings/mybinding/scaling_events?page=2"}
+	  }
+	}
+	`
+
+	scalingEventsPage2 := `
+	{
+	  "resources": [
+	    {
+	      "guid": "event-2",
+	      "service_binding_guid": "mybinding",
+	      "scaling_factor": -1,
+	      "description": "Scaled down from 3 to 2 instances.",
+	      "created_at": "2016-12-30T01:25:00Z"
+	    }
+	  ],
+	  "pagination": {
+	    "total_results": 3,
+	    "total_pages": 2
+	  }
+	}
+	`
+
+	Context("Given a binding with multiple pages of scaling events", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/bindings/mybinding/scaling_events"),
+					ghttp.VerifyHeader(http.Header{
+						"Authorization": []string{"Bearer test-token"},
+					}),
+					ghttp.RespondWith(http.StatusOK, scalingEventsPage1),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/bindings/mybinding/scaling_events", "page=2"),
+					ghttp.VerifyHeader(http.Header{
+						"Authorization": []string{"Bearer test-token"},
+					}),
+					ghttp.RespondWith(http.StatusOK, scalingEventsPage2),
+				),
+			)
+		})
+
+		It("Should retrieve the scaling decisions across all pages in chronological order", func() {
+			client, _ := autoscaler.NewClient(config)
+			decisions, err := client.GetScalingDecisions("mybinding", nil)
+			Ω(err).Should(BeNil())
+			Ω(len(decisions)).Should(Equal(3))
+			Ω(decisions[0].GUID).Should(Equal("event-1"))
+			Ω(decisions[1].GUID).Should(Equal("event-2"))
+			Ω(decisions[2].GUID).Should(Equal("event-3"))
+			Ω(decisions[1].ScalingFactor).Should(Equal(-1))
+		})
+
+		It("Should narrow down the scaling decisions to a time range", func() {
+			client, _ := autoscaler.NewClient(config)
+			since, _ := time.Parse(time.RFC3339, "2016-12-30T00:00:00Z")
+			until, _ := time.Parse(time.RFC3339, "2016-12-30T02:00:00Z")
+			decisions, err := client.GetScalingDecisions("mybinding", &autoscaler.ScalingDecisionsFilter{
+				Since: since,
+				Until: until,
+			})
+			Ω(err).Should(BeNil())
+			Ω(len(decisions)).Should(Equal(1))
+			Ω(decisions[0].GUID).Should(Equal("event-2"))
+		})
+
+		It("Should return only the most recent decisions when limited", func() {
+			client, _ := autoscaler.NewClient(config)
+			decisions, err := client.GetScalingDecisions("mybinding", &autoscaler.ScalingDecisionsFilter{
+				MaxResults: 2,
+			})
+			Ω(err).Should(BeNil())
+			Ω(len(decisions)).Should(Equal(2))
+			Ω(decisions[0].GUID).Should(Equal("event-2"))
+			Ω(decisions[1].GUID).Should(Equal("event-3"))
+		})
+	})
 })
+
+// verifyEnabledSent verifies the enabled flag sent in a binding update, nil if left out
+func verifyEnabledSent(enabled interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		var sent map[string]interface{}
+		Ω(json.NewDecoder(req.Body).Decode(&sent)).Should(Succeed())
+		Ω(sent["min_instances"]).Should(BeNumerically("==", 2))
+		if enabled == nil {
+			Ω(sent).ShouldNot(HaveKey("enabled"))
+		} else {
+			Ω(sent["enabled"]).Should(Equal(enabled))
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package autoscalertest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bijukunjummen/app-autoscaler-client"
+)
+
+// ccResource is a resource of the fake Cloud Controller v2 API, the entity holding names and the GUIDs of parents
+type ccResource struct {
+	guid   string
+	entity map[string]string
+}
+
+// AddApp adds an application to the fake Cloud Controller, along with its org and space if they do not exist yet,
+// returning the application GUID
+func (server *Server) AddApp(org, space, app string) string {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	orgGUID := server.ensureCCResource("organizations", map[string]string{"name": org})
+	spaceGUID := server.ensureCCResource("spaces", map[string]string{"name": space, "organization_guid": orgGUID})
+	return server.ensureCCResource("apps", map[string]string{"name": app, "space_guid": spaceGUID})
+}
+
+// AddServiceInstance names the service instance of the server in the fake Cloud Controller,
+// adding the org and space it belongs to if they do not exist yet
+func (server *Server) AddServiceInstance(org, space, name string) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.addServiceInstance(server.InstanceGUID, org, space, name)
+}
+
+// NewServiceInstance adds another autoscaler service instance to the server, known to the fake Cloud Controller,
+// returning its GUID. Bindings are added to it with AddInstanceBinding.
+func (server *Server) NewServiceInstance(org, space, name string) string {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	instanceGUID := NewGUID()
+	server.addServiceInstance(instanceGUID, org, space, name)
+	return instanceGUID
+}
+
+// addServiceInstance adds an instance of the autoscaler service to the fake Cloud Controller
+func (server *Server) addServiceInstance(instanceGUID, org, space, name string) {
+	orgGUID := server.ensureCCResource("organizations", map[string]string{"name": org})
+	spaceGUID := server.ensureCCResource("spaces", map[string]string{"name": space, "organization_guid": orgGUID})
+	serviceGUID := server.ensureCCResource("services", map[string]string{"label": autoscaler.AutoscalerServiceLabel})
+	planGUID := server.ensureCCResource("service_plans", map[string]string{"name": "standard", "service_guid": serviceGUID})
+	server.cc["service_instances"] = append(server.cc["service_instances"], ccResource{
+		guid: instanceGUID,
+		entity: map[string]string{
+			"name":              name,
+			"space_guid":        spaceGUID,
+			"service_plan_guid": planGUID,
+			"dashboard_url":     server.URL + "/dashboard/instances/" + instanceGUID,
+		},
+	})
+}
+
+// BindApp adds a binding of an application added with AddApp to the service instance of the server,
+// the binding is both known to the fake Cloud Controller and to the fake Autoscaler API
+func (server *Server) BindApp(appGUID string, binding autoscaler.Binding) *autoscaler.BindingResource {
+	resource := server.AddBinding(binding)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.cc["service_bindings"] = append(server.cc["service_bindings"], ccResource{
+		guid: resource.GUID,
+		entity: map[string]string{
+			"app_guid":              appGUID,
+			"service_instance_guid": server.InstanceGUID,
+		},
+	})
+	return resource
+}
+
+// ensureCCResource returns the GUID of the resource of a collection with the same entity, adding it if missing
+func (server *Server) ensureCCResource(collection string, entity map[string]string) string {
+	for _, resource := range server.cc[collection] {
+		if matchesEntity(resource.entity, entity) {
+			return resource.guid
+		}
+	}
+	resource := ccResource{guid: NewGUID(), entity: entity}
+	server.cc[collection] = append(server.cc[collection], resource)
+	return resource.guid
+}
+
+// serveCC serves the lists of the Cloud Controller v2 API, like /v2/apps or /v2/spaces/:guid/apps,
+// filtered by their q=field:value query parameters
+func (server *Server) serveCC(w http.ResponseWriter, r *http.Request, path []string) {
+	if r.Method != "GET" || len(path) > 3 {
+		writeError(w, http.StatusNotFound, "not_found", "Unknown path "+r.URL.Path)
+		return
+	}
+	if len(path) == 2 {
+		for _, resource := range server.cc[path[0]] {
+			if resource.guid == path[1] {
+				writeJSON(w, http.StatusOK, ccDocument(path[0], resource))
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "not_found", "Unknown path "+r.URL.Path)
+		return
+	}
+
+	filter := map[string]string{}
+	collection := path[0]
+	if len(path) == 3 {
+		filter[strings.TrimSuffix(path[0], "s")+"_guid"] = path[1]
+		collection = path[2]
+	}
+	for _, q := range r.URL.Query()["q"] {
+		parts := strings.SplitN(q, ":", 2)
+		if len(parts) != 2 {
+			writeError(w, http.StatusBadRequest, "bad_request", "Unsupported query "+q)
+			return
+		}
+		filter[parts[0]] = parts[1]
+	}
+
+	var resources []interface{}
+	for _, resource := range server.cc[collection] {
+		if matchesEntity(resource.entity, filter) {
+			resources = append(resources, ccDocument(collection, resource))
+		}
+	}
+	server.writeCCPage(w, r, resources)
+}
+
+// writeCCPage writes the page of resources asked for through the page and results-per-page query parameters,
+// linking to the next page through next_url like Cloud Controller does
+func (server *Server) writeCCPage(w http.ResponseWriter, r *http.Request, resources []interface{}) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("results-per-page"))
+	if perPage < 1 {
+		perPage = server.PageSize
+	}
+	totalPages := (len(resources) + perPage - 1) / perPage
+
+	start, end := (page-1)*perPage, page*perPage
+	if start > len(resources) {
+		start = len(resources)
+	}
+	if end > len(resources) {
+		end = len(resources)
+	}
+
+	var nextURL interface{}
+	if page < totalPages {
+		query := r.URL.Query()
+		query.Set("page", strconv.Itoa(page+1))
+		query.Set("results-per-page", strconv.Itoa(perPage))
+		nextURL = r.URL.Path + "?" + query.Encode()
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"total_results": len(resources),
+		"total_pages":   totalPages,
+		"next_url":      nextURL,
+		"resources":     append([]interface{}{}, resources[start:end]...),
+	})
+}
+
+// ccDocument returns the resource of a collection as served by Cloud Controller
+func ccDocument(collection string, resource ccResource) map[string]interface{} {
+	return map[string]interface{}{
+		"metadata": map[string]string{"guid": resource.guid, "url": "/v2/" + collection + "/" + resource.guid},
+		"entity":   resource.entity,
+	}
+}
+
+func matchesEntity(entity, filter map[string]string) bool {
+	for field, value := range filter {
+		if entity[field] != value {
+			return false
+		}
+	}
+	return true
+}
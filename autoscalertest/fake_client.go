@@ -0,0 +1,618 @@
+package autoscalertest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bijukunjummen/app-autoscaler-client"
+	"golang.org/x/net/context"
+)
+
+// Call is a call made to a FakeClient, Args holds the arguments of the call other than the context
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// FakeClient is a recording fake of autoscaler.Client. A call fails with the error set for its method if any,
+// else is answered by the stub set for its method if any, else from the bindings, scheduled limit changes, rules and
+// scaling decisions the fake has been seeded with. Stubs have to be set before the fake is used.
+// The Context variants of the methods are recorded under the name of the method without the Context suffix.
+type FakeClient struct {
+	GetServiceBindingsStub         func() (*autoscaler.ServiceInstances, error)
+	GetBindingStub                 func(bindingGUID string) (*autoscaler.BindingResource, error)
+	UpdateBindingStub              func(bindingGUID string, binding *autoscaler.Binding) (*autoscaler.BindingResource, error)
+	UpdateBindingEnabledStub       func(bindingGUID string, binding *autoscaler.Binding) (*autoscaler.BindingResource, error)
+	GetScalingDecisionsStub        func(bindingGUID string, filter *autoscaler.ScalingDecisionsFilter) ([]autoscaler.ScalingDecision, error)
+	GetScheduledLimitChangesStub   func(bindingGUID string) ([]autoscaler.ScheduledLimitChange, error)
+	CreateScheduledLimitChangeStub func(bindingGUID string, scheduledLimitChange *autoscaler.ScheduledLimitChange) (*autoscaler.ScheduledLimitChange, error)
+	UpdateScheduledLimitChangeStub func(bindingGUID string, changeGUID string, scheduledLimitChange *autoscaler.ScheduledLimitChange) (*autoscaler.ScheduledLimitChange, error)
+	DeleteScheduledLimitChangeStub func(bindingGUID string, changeGUID string) error
+	GetRulesStub                   func(bindingGUID string) ([]autoscaler.Rule, error)
+	CreateRuleStub                 func(bindingGUID string, rule *autoscaler.Rule) (*autoscaler.Rule, error)
+	UpdateRuleStub                 func(bindingGUID string, ruleGUID string, rule *autoscaler.Rule) (*autoscaler.Rule, error)
+	DeleteRuleStub                 func(bindingGUID string, ruleGUID string) error
+	FollowStub                     func(binding *autoscaler.BindingResource, rel string, out interface{}) error
+	FindBindingByAppNameStub       func(org, space, app string) (*autoscaler.BindingResource, error)
+	FindBindingByAppGUIDStub       func(appGUID string) (*autoscaler.BindingResource, error)
+
+	mu        sync.Mutex
+	calls     []Call
+	errors    map[string]error
+	bindings  []autoscaler.BindingResource
+	schedules map[string][]autoscaler.ScheduledLimitChange
+	rules     map[string][]autoscaler.Rule
+	events    map[string][]autoscaler.ScalingDecision
+	apps      map[string]fakeApp
+}
+
+// fakeApp is the application a seeded binding belongs to
+type fakeApp struct {
+	org, space, guid string
+}
+
+var _ autoscaler.Client = &FakeClient{}
+
+// NewFakeClient creates a FakeClient without any binding
+func NewFakeClient() *FakeClient {
+	return &FakeClient{
+		errors:    make(map[string]error),
+		schedules: make(map[string][]autoscaler.ScheduledLimitChange),
+		rules:     make(map[string][]autoscaler.Rule),
+		events:    make(map[string][]autoscaler.ScalingDecision),
+		apps:      make(map[string]fakeApp),
+	}
+}
+
+// AddBinding seeds the fake with a binding, a GUID is generated if not set
+func (fake *FakeClient) AddBinding(binding autoscaler.BindingResource) autoscaler.BindingResource {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	if binding.GUID == "" {
+		binding.GUID = NewGUID()
+	}
+	fake.bindings = append(fake.bindings, binding)
+	return binding
+}
+
+// AddAppBinding seeds the fake with the binding of an application, found by its AppName in the org and space,
+// or by the application GUID. GUIDs are generated if not set.
+func (fake *FakeClient) AddAppBinding(org, space, appGUID string, binding autoscaler.BindingResource) autoscaler.BindingResource {
+	binding = fake.AddBinding(binding)
+	if appGUID == "" {
+		appGUID = NewGUID()
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	fake.apps[binding.GUID] = fakeApp{org: org, space: space, guid: appGUID}
+	return binding
+}
+
+// AddScheduledLimitChange seeds the fake with a scheduled limit change of a binding, a GUID is generated if not set
+func (fake *FakeClient) AddScheduledLimitChange(bindingGUID string, change autoscaler.ScheduledLimitChange) autoscaler.ScheduledLimitChange {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	if change.GUID == "" {
+		change.GUID = NewGUID()
+	}
+	change.ServiceBindingGUID = bindingGUID
+	fake.schedules[bindingGUID] = append(fake.schedules[bindingGUID], change)
+	return change
+}
+
+// AddRule seeds the fake with a rule of a binding, a GUID is generated if not set
+func (fake *FakeClient) AddRule(bindingGUID string, rule autoscaler.Rule) autoscaler.Rule {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	if rule.GUID == "" {
+		rule.GUID = NewGUID()
+	}
+	rule.ServiceBindingGUID = bindingGUID
+	fake.rules[bindingGUID] = append(fake.rules[bindingGUID], rule)
+	return rule
+}
+
+// AddScalingDecision seeds the fake with a scaling decision of a binding, a GUID is generated if not set
+func (fake *FakeClient) AddScalingDecision(bindingGUID string, decision autoscaler.ScalingDecision) autoscaler.ScalingDecision {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	if decision.GUID == "" {
+		decision.GUID = NewGUID()
+	}
+	decision.ServiceBindingGUID = bindingGUID
+	fake.events[bindingGUID] = append(fake.events[bindingGUID], decision)
+	return decision
+}
+
+// SetError makes every further call to a method, like "GetBinding", fail with the error, nil clears it
+func (fake *FakeClient) SetError(method string, err error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	fake.errors[method] = err
+}
+
+// Calls returns every call made to the fake, in order
+func (fake *FakeClient) Calls() []Call {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	return append([]Call(nil), fake.calls...)
+}
+
+// CallsTo returns the calls made to a method, like "GetBinding", in order
+func (fake *FakeClient) CallsTo(method string) []Call {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	var calls []Call
+	for _, call := range fake.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// CallCount returns the number of calls made to a method, like "GetBinding"
+func (fake *FakeClient) CallCount(method string) int {
+	return len(fake.CallsTo(method))
+}
+
+// record records a call, returning the error set for the method if any
+func (fake *FakeClient) record(method string, args ...interface{}) error {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	fake.calls = append(fake.calls, Call{Method: method, Args: args})
+	return fake.errors[method]
+}
+
+func (fake *FakeClient) findBinding(bindingGUID string) *autoscaler.BindingResource {
+	for i := range fake.bindings {
+		if fake.bindings[i].GUID == bindingGUID {
+			return &fake.bindings[i]
+		}
+	}
+	return nil
+}
+
+func notFound(method, path string) error {
+	return &autoscaler.APIError{
+		StatusCode: http.StatusNotFound,
+		Method:     method,
+		URL:        path,
+		Response:   &autoscaler.ErrorResponse{Error: "not_found", Description: path + " not found"},
+	}
+}
+
+// GetServiceBindings ...
+func (fake *FakeClient) GetServiceBindings() (*autoscaler.ServiceInstances, error) {
+	return fake.GetServiceBindingsContext(context.Background())
+}
+
+// GetServiceBindingsContext ...
+func (fake *FakeClient) GetServiceBindingsContext(ctx context.Context) (*autoscaler.ServiceInstances, error) {
+	if err := fake.record("GetServiceBindings"); err != nil {
+		return nil, err
+	}
+	if fake.GetServiceBindingsStub != nil {
+		return fake.GetServiceBindingsStub()
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	return &autoscaler.ServiceInstances{
+		BindingResources: append([]autoscaler.BindingResource(nil), fake.bindings...),
+	}, nil
+}
+
+// GetBinding ...
+func (fake *FakeClient) GetBinding(bindingGUID string) (*autoscaler.BindingResource, error) {
+	return fake.GetBindingContext(context.Background(), bindingGUID)
+}
+
+// GetBindingContext ...
+func (fake *FakeClient) GetBindingContext(ctx context.Context, bindingGUID string) (*autoscaler.BindingResource, error) {
+	if err := fake.record("GetBinding", bindingGUID); err != nil {
+		return nil, err
+	}
+	if fake.GetBindingStub != nil {
+		return fake.GetBindingStub(bindingGUID)
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	binding := fake.findBinding(bindingGUID)
+	if binding == nil {
+		return nil, notFound("GET", "/bindings/"+bindingGUID)
+	}
+	resource := *binding
+	resource.Relationships.Rules = append([]autoscaler.Rule(nil), fake.rules[bindingGUID]...)
+	return &resource, nil
+}
+
+// UpdateBinding ...
+func (fake *FakeClient) UpdateBinding(bindingGUID string, binding *autoscaler.Binding) (*autoscaler.BindingResource, error) {
+	return fake.UpdateBindingContext(context.Background(), bindingGUID, binding)
+}
+
+// UpdateBindingContext ...
+func (fake *FakeClient) UpdateBindingContext(ctx context.Context, bindingGUID string, binding *autoscaler.Binding) (*autoscaler.BindingResource, error) {
+	if err := fake.record("UpdateBinding", bindingGUID, binding); err != nil {
+		return nil, err
+	}
+	if fake.UpdateBindingStub != nil {
+		return fake.UpdateBindingStub(bindingGUID, binding)
+	}
+	return fake.updateBinding(bindingGUID, binding, binding.Enabled)
+}
+
+// UpdateBindingEnabled ...
+func (fake *FakeClient) UpdateBindingEnabled(bindingGUID string, binding *autoscaler.Binding) (*autoscaler.BindingResource, error) {
+	return fake.UpdateBindingEnabledContext(context.Background(), bindingGUID, binding)
+}
+
+// UpdateBindingEnabledContext ...
+func (fake *FakeClient) UpdateBindingEnabledContext(ctx context.Context, bindingGUID string, binding *autoscaler.Binding) (*autoscaler.BindingResource, error) {
+	if err := fake.record("UpdateBindingEnabled", bindingGUID, binding); err != nil {
+		return nil, err
+	}
+	if fake.UpdateBindingEnabledStub != nil {
+		return fake.UpdateBindingEnabledStub(bindingGUID, binding)
+	}
+	return fake.updateBinding(bindingGUID, binding, true)
+}
+
+// updateBinding updates the seeded binding with the fields sent, like the API does: the limits if not zero, being
+// omitted otherwise, and the enabled flag only if sent
+func (fake *FakeClient) updateBinding(bindingGUID string, binding *autoscaler.Binding, sendsEnabled bool) (*autoscaler.BindingResource, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	existing := fake.findBinding(bindingGUID)
+	if existing == nil {
+		return nil, notFound("PUT", "/bindings/"+bindingGUID)
+	}
+	if binding.MinInstances != 0 {
+		existing.MinInstances = binding.MinInstances
+	}
+	if binding.MaxInstances != 0 {
+		existing.MaxInstances = binding.MaxInstances
+	}
+	if sendsEnabled {
+		existing.Enabled = binding.Enabled
+	}
+	resource := *existing
+	return &resource, nil
+}
+
+// GetScalingDecisions ...
+func (fake *FakeClient) GetScalingDecisions(bindingGUID string, filter *autoscaler.ScalingDecisionsFilter) ([]autoscaler.ScalingDecision, error) {
+	return fake.GetScalingDecisionsContext(context.Background(), bindingGUID, filter)
+}
+
+// GetScalingDecisionsContext ...
+func (fake *FakeClient) GetScalingDecisionsContext(ctx context.Context, bindingGUID string, filter *autoscaler.ScalingDecisionsFilter) ([]autoscaler.ScalingDecision, error) {
+	if err := fake.record("GetScalingDecisions", bindingGUID, filter); err != nil {
+		return nil, err
+	}
+	if fake.GetScalingDecisionsStub != nil {
+		return fake.GetScalingDecisionsStub(bindingGUID, filter)
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	if filter == nil {
+		filter = &autoscaler.ScalingDecisionsFilter{}
+	}
+	var decisions []autoscaler.ScalingDecision
+	for _, decision := range fake.events[bindingGUID] {
+		if decision.CreatedAt != nil {
+			if !filter.Since.IsZero() && decision.CreatedAt.Before(filter.Since) {
+				continue
+			}
+			if !filter.Until.IsZero() && decision.CreatedAt.After(filter.Until) {
+				continue
+			}
+		}
+		decisions = append(decisions, decision)
+	}
+	sort.SliceStable(decisions, func(i, j int) bool {
+		return createdAt(decisions[i]).Before(createdAt(decisions[j]))
+	})
+	if filter.MaxResults > 0 && len(decisions) > filter.MaxResults {
+		decisions = decisions[len(decisions)-filter.MaxResults:]
+	}
+	return decisions, nil
+}
+
+func createdAt(decision autoscaler.ScalingDecision) time.Time {
+	if decision.CreatedAt == nil {
+		return time.Time{}
+	}
+	return *decision.CreatedAt
+}
+
+// GetScheduledLimitChanges ...
+func (fake *FakeClient) GetScheduledLimitChanges(bindingGUID string) ([]autoscaler.ScheduledLimitChange, error) {
+	return fake.GetScheduledLimitChangesContext(context.Background(), bindingGUID)
+}
+
+// GetScheduledLimitChangesContext ...
+func (fake *FakeClient) GetScheduledLimitChangesContext(ctx context.Context, bindingGUID string) ([]autoscaler.ScheduledLimitChange, error) {
+	if err := fake.record("GetScheduledLimitChanges", bindingGUID); err != nil {
+		return nil, err
+	}
+	if fake.GetScheduledLimitChangesStub != nil {
+		return fake.GetScheduledLimitChangesStub(bindingGUID)
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	return append([]autoscaler.ScheduledLimitChange(nil), fake.schedules[bindingGUID]...), nil
+}
+
+// CreateScheduledLimitChange ...
+func (fake *FakeClient) CreateScheduledLimitChange(bindingGUID string, scheduledLimitChange *autoscaler.ScheduledLimitChange) (*autoscaler.ScheduledLimitChange, error) {
+	return fake.CreateScheduledLimitChangeContext(context.Background(), bindingGUID, scheduledLimitChange)
+}
+
+// CreateScheduledLimitChangeContext ...
+func (fake *FakeClient) CreateScheduledLimitChangeContext(ctx context.Context, bindingGUID string, scheduledLimitChange *autoscaler.ScheduledLimitChange) (*autoscaler.ScheduledLimitChange, error) {
+	if err := fake.record("CreateScheduledLimitChange", bindingGUID, scheduledLimitChange); err != nil {
+		return nil, err
+	}
+	if fake.CreateScheduledLimitChangeStub != nil {
+		return fake.CreateScheduledLimitChangeStub(bindingGUID, scheduledLimitChange)
+	}
+	change := *scheduledLimitChange
+	change.GUID = ""
+	created := fake.AddScheduledLimitChange(bindingGUID, change)
+	return &created, nil
+}
+
+// UpdateScheduledLimitChange ...
+func (fake *FakeClient) UpdateScheduledLimitChange(bindingGUID string, changeGUID string, scheduledLimitChange *autoscaler.ScheduledLimitChange) (*autoscaler.ScheduledLimitChange, error) {
+	return fake.UpdateScheduledLimitChangeContext(context.Background(), bindingGUID, changeGUID, scheduledLimitChange)
+}
+
+// UpdateScheduledLimitChangeContext ...
+func (fake *FakeClient) UpdateScheduledLimitChangeContext(ctx context.Context, bindingGUID string, changeGUID string, scheduledLimitChange *autoscaler.ScheduledLimitChange) (*autoscaler.ScheduledLimitChange, error) {
+	if err := fake.record("UpdateScheduledLimitChange", bindingGUID, changeGUID, scheduledLimitChange); err != nil {
+		return nil, err
+	}
+	if fake.UpdateScheduledLimitChangeStub != nil {
+		return fake.UpdateScheduledLimitChangeStub(bindingGUID, changeGUID, scheduledLimitChange)
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	changes := fake.schedules[bindingGUID]
+	for i := range changes {
+		if changes[i].GUID == changeGUID {
+			changes[i] = *scheduledLimitChange
+			changes[i].GUID, changes[i].ServiceBindingGUID = changeGUID, bindingGUID
+			updated := changes[i]
+			return &updated, nil
+		}
+	}
+	return nil, notFound("PUT", "/bindings/"+bindingGUID+"/scheduled_limit_changes/"+changeGUID)
+}
+
+// DeleteScheduledLimitChange ...
+func (fake *FakeClient) DeleteScheduledLimitChange(bindingGUID string, changeGUID string) error {
+	return fake.DeleteScheduledLimitChangeContext(context.Background(), bindingGUID, changeGUID)
+}
+
+// DeleteScheduledLimitChangeContext ...
+func (fake *FakeClient) DeleteScheduledLimitChangeContext(ctx context.Context, bindingGUID string, changeGUID string) error {
+	if err := fake.record("DeleteScheduledLimitChange", bindingGUID, changeGUID); err != nil {
+		return err
+	}
+	if fake.DeleteScheduledLimitChangeStub != nil {
+		return fake.DeleteScheduledLimitChangeStub(bindingGUID, changeGUID)
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	changes := fake.schedules[bindingGUID]
+	for i := range changes {
+		if changes[i].GUID == changeGUID {
+			fake.schedules[bindingGUID] = append(changes[:i], changes[i+1:]...)
+			return nil
+		}
+	}
+	return notFound("DELETE", "/bindings/"+bindingGUID+"/scheduled_limit_changes/"+changeGUID)
+}
+
+// GetRules ...
+func (fake *FakeClient) GetRules(bindingGUID string) ([]autoscaler.Rule, error) {
+	return fake.GetRulesContext(context.Background(), bindingGUID)
+}
+
+// GetRulesContext ...
+func (fake *FakeClient) GetRulesContext(ctx context.Context, bindingGUID string) ([]autoscaler.Rule, error) {
+	if err := fake.record("GetRules", bindingGUID); err != nil {
+		return nil, err
+	}
+	if fake.GetRulesStub != nil {
+		return fake.GetRulesStub(bindingGUID)
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	return append([]autoscaler.Rule(nil), fake.rules[bindingGUID]...), nil
+}
+
+// CreateRule ...
+func (fake *FakeClient) CreateRule(bindingGUID string, rule *autoscaler.Rule) (*autoscaler.Rule, error) {
+	return fake.CreateRuleContext(context.Background(), bindingGUID, rule)
+}
+
+// CreateRuleContext ...
+func (fake *FakeClient) CreateRuleContext(ctx context.Context, bindingGUID string, rule *autoscaler.Rule) (*autoscaler.Rule, error) {
+	if err := fake.record("CreateRule", bindingGUID, rule); err != nil {
+		return nil, err
+	}
+	if fake.CreateRuleStub != nil {
+		return fake.CreateRuleStub(bindingGUID, rule)
+	}
+	newRule := *rule
+	newRule.GUID = ""
+	created := fake.AddRule(bindingGUID, newRule)
+	return &created, nil
+}
+
+// UpdateRule ...
+func (fake *FakeClient) UpdateRule(bindingGUID string, ruleGUID string, rule *autoscaler.Rule) (*autoscaler.Rule, error) {
+	return fake.UpdateRuleContext(context.Background(), bindingGUID, ruleGUID, rule)
+}
+
+// UpdateRuleContext ...
+func (fake *FakeClient) UpdateRuleContext(ctx context.Context, bindingGUID string, ruleGUID string, rule *autoscaler.Rule) (*autoscaler.Rule, error) {
+	if err := fake.record("UpdateRule", bindingGUID, ruleGUID, rule); err != nil {
+		return nil, err
+	}
+	if fake.UpdateRuleStub != nil {
+		return fake.UpdateRuleStub(bindingGUID, ruleGUID, rule)
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	rules := fake.rules[bindingGUID]
+	for i := range rules {
+		if rules[i].GUID == ruleGUID {
+			rules[i] = *rule
+			rules[i].GUID, rules[i].ServiceBindingGUID = ruleGUID, bindingGUID
+			updated := rules[i]
+			return &updated, nil
+		}
+	}
+	return nil, notFound("PUT", "/bindings/"+bindingGUID+"/rules/"+ruleGUID)
+}
+
+// DeleteRule ...
+func (fake *FakeClient) DeleteRule(bindingGUID string, ruleGUID string) error {
+	return fake.DeleteRuleContext(context.Background(), bindingGUID, ruleGUID)
+}
+
+// DeleteRuleContext ...
+func (fake *FakeClient) DeleteRuleContext(ctx context.Context, bindingGUID string, ruleGUID string) error {
+	if err := fake.record("DeleteRule", bindingGUID, ruleGUID); err != nil {
+		return err
+	}
+	if fake.DeleteRuleStub != nil {
+		return fake.DeleteRuleStub(bindingGUID, ruleGUID)
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	rules := fake.rules[bindingGUID]
+	for i := range rules {
+		if rules[i].GUID == ruleGUID {
+			fake.rules[bindingGUID] = append(rules[:i], rules[i+1:]...)
+			return nil
+		}
+	}
+	return notFound("DELETE", "/bindings/"+bindingGUID+"/rules/"+ruleGUID)
+}
+
+// Follow ...
+func (fake *FakeClient) Follow(binding *autoscaler.BindingResource, rel string, out interface{}) error {
+	return fake.FollowContext(context.Background(), binding, rel, out)
+}
+
+// FollowContext answers the self, events and scheduled_limit_changes links from the seeded resources
+func (fake *FakeClient) FollowContext(ctx context.Context, binding *autoscaler.BindingResource, rel string, out interface{}) error {
+	if err := fake.record("Follow", binding, rel, out); err != nil {
+		return err
+	}
+	if fake.FollowStub != nil {
+		return fake.FollowStub(binding, rel, out)
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	var linked interface{}
+	switch rel {
+	case "self":
+		existing := fake.findBinding(binding.GUID)
+		if existing == nil {
+			return notFound("GET", "/bindings/"+binding.GUID)
+		}
+		linked = existing
+	case "events":
+		linked = fake.events[binding.GUID]
+	case "scheduled_limit_changes":
+		linked = fake.schedules[binding.GUID]
+	default:
+		return fmt.Errorf("Binding %s has no %s link", binding.GUID, rel)
+	}
+
+	encoded, err := json.Marshal(linked)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, out)
+}
+
+// FindBindingByAppName ...
+func (fake *FakeClient) FindBindingByAppName(org, space, app string) (*autoscaler.BindingResource, error) {
+	return fake.FindBindingByAppNameContext(context.Background(), org, space, app)
+}
+
+// FindBindingByAppNameContext answers from the bindings seeded with AddAppBinding
+func (fake *FakeClient) FindBindingByAppNameContext(ctx context.Context, org, space, app string) (*autoscaler.BindingResource, error) {
+	if err := fake.record("FindBindingByAppName", org, space, app); err != nil {
+		return nil, err
+	}
+	if fake.FindBindingByAppNameStub != nil {
+		return fake.FindBindingByAppNameStub(org, space, app)
+	}
+	return fake.findAppBinding(func(binding *autoscaler.BindingResource, owner fakeApp) bool {
+		return owner.org == org && owner.space == space && binding.AppName == app
+	}, app)
+}
+
+// FindBindingByAppGUID ...
+func (fake *FakeClient) FindBindingByAppGUID(appGUID string) (*autoscaler.BindingResource, error) {
+	return fake.FindBindingByAppGUIDContext(context.Background(), appGUID)
+}
+
+// FindBindingByAppGUIDContext answers from the bindings seeded with AddAppBinding
+func (fake *FakeClient) FindBindingByAppGUIDContext(ctx context.Context, appGUID string) (*autoscaler.BindingResource, error) {
+	if err := fake.record("FindBindingByAppGUID", appGUID); err != nil {
+		return nil, err
+	}
+	if fake.FindBindingByAppGUIDStub != nil {
+		return fake.FindBindingByAppGUIDStub(appGUID)
+	}
+	return fake.findAppBinding(func(binding *autoscaler.BindingResource, owner fakeApp) bool {
+		return owner.guid == appGUID
+	}, appGUID)
+}
+
+func (fake *FakeClient) findAppBinding(matches func(binding *autoscaler.BindingResource, owner fakeApp) bool, name string) (*autoscaler.BindingResource, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	for i := range fake.bindings {
+		owner, ok := fake.apps[fake.bindings[i].GUID]
+		if ok && matches(&fake.bindings[i], owner) {
+			resource := fake.bindings[i]
+			return &resource, nil
+		}
+	}
+	return nil, &autoscaler.NotFoundError{Resource: "binding of app", Name: name}
+}
@@ -0,0 +1,113 @@
+package autoscalertest_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"errors"
+
+	"github.com/bijukunjummen/app-autoscaler-client"
+	"github.com/bijukunjummen/app-autoscaler-client/autoscalertest"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Fake Client", func() {
+	var fake *autoscalertest.FakeClient
+	var binding autoscaler.BindingResource
+
+	BeforeEach(func() {
+		fake = autoscalertest.NewFakeClient()
+		binding = fake.AddBinding(autoscaler.BindingResource{
+			Binding: autoscaler.Binding{AppName: "checkout", MinInstances: 2, MaxInstances: 5},
+		})
+		fake.AddScheduledLimitChange(binding.GUID, autoscaler.ScheduledLimitChange{MinInstances: 4, MaxInstances: 8})
+	})
+
+	It("Should answer from the seeded bindings and schedules", func() {
+		var client autoscaler.Client = fake
+
+		serviceInstances, err := client.GetServiceBindings()
+		Ω(err).Should(BeNil())
+		Ω(len(serviceInstances.BindingResources)).Should(Equal(1))
+
+		changes, err := client.GetScheduledLimitChangesContext(context.Background(), binding.GUID)
+		Ω(err).Should(BeNil())
+		Ω(len(changes)).Should(Equal(1))
+		Ω(changes[0].ServiceBindingGUID).Should(Equal(binding.GUID))
+
+		_, err = client.GetBinding("unknown")
+		Ω(autoscaler.IsNotFound(err)).Should(BeTrue())
+	})
+
+	It("Should record every call along with its arguments", func() {
+		update := &autoscaler.Binding{MinInstances: 3, MaxInstances: 6}
+		fake.GetBinding(binding.GUID)
+		fake.UpdateBindingContext(context.Background(), binding.GUID, update)
+		fake.GetBinding(binding.GUID)
+
+		Ω(fake.CallCount("GetBinding")).Should(Equal(2))
+		Ω(fake.CallsTo("UpdateBinding")).Should(Equal([]autoscalertest.Call{
+			{Method: "UpdateBinding", Args: []interface{}{binding.GUID, update}},
+		}))
+		Ω(fake.Calls()[1].Method).Should(Equal("UpdateBinding"))
+
+		updated, _ := fake.GetBinding(binding.GUID)
+		Ω(updated.MinInstances).Should(Equal(3))
+	})
+
+	It("Should only update the limits sent", func() {
+		_, err := fake.UpdateBinding(binding.GUID, &autoscaler.Binding{MaxInstances: 8})
+		Ω(err).Should(BeNil())
+
+		updated, _ := fake.GetBinding(binding.GUID)
+		Ω(updated.MinInstances).Should(Equal(2))
+		Ω(updated.MaxInstances).Should(Equal(8))
+	})
+
+	It("Should return the stubbed results", func() {
+		fake.GetRulesStub = func(bindingGUID string) ([]autoscaler.Rule, error) {
+			return []autoscaler.Rule{{Type: "memory"}}, nil
+		}
+
+		rules, err := fake.GetRules(binding.GUID)
+
+		Ω(err).Should(BeNil())
+		Ω(rules[0].Type).Should(Equal("memory"))
+	})
+
+	It("Should fail with the error set for a method", func() {
+		failure := errors.New("failure")
+		fake.SetError("DeleteScheduledLimitChange", failure)
+
+		err := fake.DeleteScheduledLimitChange(binding.GUID, "changeid")
+
+		Ω(err).Should(Equal(failure))
+		Ω(fake.CallCount("DeleteScheduledLimitChange")).Should(Equal(1))
+	})
+
+	It("Should follow links to the seeded resources", func() {
+		var changes []autoscaler.ScheduledLimitChange
+
+		err := fake.Follow(&binding, "scheduled_limit_changes", &changes)
+
+		Ω(err).Should(BeNil())
+		Ω(changes[0].MaxInstances).Should(Equal(8))
+	})
+
+	It("Should find the seeded bindings of applications", func() {
+		checkout := fake.AddAppBinding("acme", "production", "app-1", autoscaler.BindingResource{
+			Binding: autoscaler.Binding{AppName: "checkout"},
+		})
+
+		found, err := fake.FindBindingByAppName("acme", "production", "checkout")
+		Ω(err).Should(BeNil())
+		Ω(found.GUID).Should(Equal(checkout.GUID))
+
+		found, err = fake.FindBindingByAppGUID("app-1")
+		Ω(err).Should(BeNil())
+		Ω(found.GUID).Should(Equal(checkout.GUID))
+
+		_, err = fake.FindBindingByAppName("acme", "staging", "checkout")
+		Ω(autoscaler.IsNotFound(err)).Should(BeTrue())
+	})
+})
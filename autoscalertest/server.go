@@ -0,0 +1,453 @@
+// Package autoscalertest provides fakes of the App Autoscaler API and of the Autoscaler Client for testing.
+package autoscalertest
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bijukunjummen/app-autoscaler-client"
+)
+
+// Server is an in-process fake of the Cloud Controller, UAA token and Autoscaler APIs, keeping its state in memory.
+// Cloud Controller serves /v2/info and the lists of orgs, spaces, apps and service bindings, the Autoscaler API
+// is served under /api.
+type Server struct {
+	*httptest.Server
+
+	// InstanceGUID is the autoscaler service instance the bindings added to the server belong to
+	InstanceGUID string
+	// Token is the access token handed out by the fake UAA, and required by the Autoscaler API
+	Token string
+	// PageSize is the number of resources in a page of a list, when the client does not ask for a page size
+	PageSize int
+
+	mu        sync.Mutex
+	bindings  []*autoscaler.BindingResource
+	instances map[string]string
+	schedules map[string][]autoscaler.ScheduledLimitChange
+	rules     map[string][]autoscaler.Rule
+	events    map[string][]autoscaler.ScalingDecision
+	cc        map[string][]ccResource
+}
+
+// NewServer starts a new fake server, it has to be closed once done
+func NewServer() *Server {
+	server := &Server{
+		InstanceGUID: NewGUID(),
+		Token:        "autoscalertest-token",
+		PageSize:     50,
+		schedules:    make(map[string][]autoscaler.ScheduledLimitChange),
+		rules:        make(map[string][]autoscaler.Rule),
+		events:       make(map[string][]autoscaler.ScalingDecision),
+		cc:           make(map[string][]ccResource),
+		instances:    make(map[string]string),
+	}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.serveHTTP))
+	return server
+}
+
+// Config returns the configuration for an Autoscaler Client talking to the server
+func (server *Server) Config() *autoscaler.Config {
+	return &autoscaler.Config{
+		CFConfig: &autoscaler.CFConfig{
+			CCApiURL: server.URL,
+			Username: "admin",
+			Password: "admin",
+		},
+		AutoscalerAPIUrl: server.URL + "/api",
+		InstanceGUID:     server.InstanceGUID,
+	}
+}
+
+// AddBinding adds a binding to the service instance of the server, a GUID is generated if not set
+func (server *Server) AddBinding(binding autoscaler.Binding) *autoscaler.BindingResource {
+	return server.AddInstanceBinding(server.InstanceGUID, binding)
+}
+
+// AddInstanceBinding adds a binding to a service instance of the server, a GUID is generated if not set
+func (server *Server) AddInstanceBinding(instanceGUID string, binding autoscaler.Binding) *autoscaler.BindingResource {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	if binding.GUID == "" {
+		binding.GUID = NewGUID()
+	}
+	now := time.Now().UTC()
+	binding.CreatedAt, binding.UpdatedAt = &now, &now
+
+	resource := &autoscaler.BindingResource{
+		Binding: binding,
+		Links: map[string]autoscaler.Link{
+			"self":                    {Href: "/api/bindings/" + binding.GUID},
+			"events":                  {Href: "/api/bindings/" + binding.GUID + "/scaling_events"},
+			"scheduled_limit_changes": {Href: "/api/bindings/" + binding.GUID + "/scheduled_limit_changes"},
+		},
+	}
+	server.bindings = append(server.bindings, resource)
+	server.instances[binding.GUID] = instanceGUID
+	return server.bindingResource(resource)
+}
+
+// AddScalingDecision records a scaling decision for a binding, a GUID is generated if not set
+func (server *Server) AddScalingDecision(bindingGUID string, decision autoscaler.ScalingDecision) autoscaler.ScalingDecision {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	if decision.GUID == "" {
+		decision.GUID = NewGUID()
+	}
+	if decision.CreatedAt == nil {
+		now := time.Now().UTC()
+		decision.CreatedAt = &now
+	}
+	decision.UpdatedAt = decision.CreatedAt
+	decision.ServiceBindingGUID = bindingGUID
+	server.events[bindingGUID] = append(server.events[bindingGUID], decision)
+	return decision
+}
+
+// Binding returns the current state of a binding
+func (server *Server) Binding(bindingGUID string) (*autoscaler.BindingResource, bool) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	if resource := server.findBinding(bindingGUID); resource != nil {
+		return server.bindingResource(resource), true
+	}
+	return nil, false
+}
+
+// ScheduledLimitChanges returns the current scheduled limit changes of a binding
+func (server *Server) ScheduledLimitChanges(bindingGUID string) []autoscaler.ScheduledLimitChange {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return append([]autoscaler.ScheduledLimitChange(nil), server.schedules[bindingGUID]...)
+}
+
+// Rules returns the current rules of a binding
+func (server *Server) Rules(bindingGUID string) []autoscaler.Rule {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return append([]autoscaler.Rule(nil), server.rules[bindingGUID]...)
+}
+
+// NewGUID generates a random GUID
+func NewGUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func (server *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/v2/info" && r.Method == "GET":
+		writeJSON(w, http.StatusOK, autoscaler.Endpoint{
+			AuthorizationEndpoint: server.URL,
+			TokenEndpoint:         server.URL,
+		})
+	case r.URL.Path == "/oauth/token" && r.Method == "POST":
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"access_token": server.Token,
+			"token_type":   "bearer",
+			"expires_in":   3600,
+		})
+	case strings.HasPrefix(r.URL.Path, "/v2/"):
+		if !server.authorized(w, r) {
+			return
+		}
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		server.serveCC(w, r, strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/v2/"), "/"), "/"))
+	case strings.HasPrefix(r.URL.Path, "/api/"):
+		if !server.authorized(w, r) {
+			return
+		}
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		server.serveAPI(w, r, strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/"), "/"), "/"))
+	default:
+		writeError(w, http.StatusNotFound, "not_found", "Unknown path "+r.URL.Path)
+	}
+}
+
+// authorized checks the request carries the token handed out by the fake UAA, responding with a 401 otherwise
+func (server *Server) authorized(w http.ResponseWriter, r *http.Request) bool {
+	if r.Header.Get("Authorization") != "Bearer "+server.Token {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Missing or invalid token")
+		return false
+	}
+	return true
+}
+
+func (server *Server) serveAPI(w http.ResponseWriter, r *http.Request, path []string) {
+	route := fmt.Sprintf("%s %s", r.Method, strings.Join(pattern(path), "/"))
+
+	if path[0] == "instances" {
+		if len(path) < 2 {
+			writeError(w, http.StatusNotFound, "not_found", "Unknown path "+r.URL.Path)
+			return
+		}
+		if route != "GET instances/*/bindings" || !server.knownInstance(path[1]) {
+			writeError(w, http.StatusNotFound, "not_found", "Unknown service instance "+path[1])
+			return
+		}
+		var bindings []interface{}
+		for _, resource := range server.bindings {
+			if server.instances[resource.GUID] == path[1] {
+				bindings = append(bindings, server.bindingResource(resource))
+			}
+		}
+		server.writePage(w, r, bindings)
+		return
+	}
+
+	if path[0] != "bindings" || len(path) < 2 {
+		writeError(w, http.StatusNotFound, "not_found", "Unknown path "+r.URL.Path)
+		return
+	}
+	resource := server.findBinding(path[1])
+	if resource == nil {
+		writeError(w, http.StatusNotFound, "not_found", "Unknown binding "+path[1])
+		return
+	}
+	bindingGUID := resource.GUID
+
+	switch route {
+	case "GET bindings/*":
+		writeJSON(w, http.StatusOK, server.bindingResource(resource))
+	case "PUT bindings/*":
+		var binding struct {
+			MinInstances *int  `json:"min_instances"`
+			MaxInstances *int  `json:"max_instances"`
+			Enabled      *bool `json:"enabled"`
+		}
+		if !readJSON(w, r, &binding) {
+			return
+		}
+		now := time.Now().UTC()
+		if binding.MinInstances != nil {
+			resource.MinInstances = *binding.MinInstances
+		}
+		if binding.MaxInstances != nil {
+			resource.MaxInstances = *binding.MaxInstances
+		}
+		if binding.Enabled != nil {
+			resource.Enabled = *binding.Enabled
+		}
+		resource.UpdatedAt = &now
+		writeJSON(w, http.StatusOK, server.bindingResource(resource))
+
+	case "GET bindings/*/scheduled_limit_changes":
+		var changes []interface{}
+		for _, change := range server.schedules[bindingGUID] {
+			changes = append(changes, change)
+		}
+		server.writePage(w, r, changes)
+	case "POST bindings/*/scheduled_limit_changes":
+		var change autoscaler.ScheduledLimitChange
+		if !readJSON(w, r, &change) {
+			return
+		}
+		now := time.Now().UTC()
+		change.GUID, change.ServiceBindingGUID = NewGUID(), bindingGUID
+		change.CreatedAt, change.UpdatedAt = &now, &now
+		server.schedules[bindingGUID] = append(server.schedules[bindingGUID], change)
+		writeJSON(w, http.StatusCreated, change)
+	case "PUT bindings/*/scheduled_limit_changes/*", "DELETE bindings/*/scheduled_limit_changes/*":
+		changes := server.schedules[bindingGUID]
+		for i := range changes {
+			if changes[i].GUID != path[3] {
+				continue
+			}
+			if r.Method == "DELETE" {
+				server.schedules[bindingGUID] = append(changes[:i], changes[i+1:]...)
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			var change autoscaler.ScheduledLimitChange
+			if !readJSON(w, r, &change) {
+				return
+			}
+			now := time.Now().UTC()
+			change.GUID, change.ServiceBindingGUID = changes[i].GUID, bindingGUID
+			change.CreatedAt, change.UpdatedAt = changes[i].CreatedAt, &now
+			changes[i] = change
+			writeJSON(w, http.StatusOK, change)
+			return
+		}
+		writeError(w, http.StatusNotFound, "not_found", "Unknown scheduled limit change "+path[3])
+
+	case "GET bindings/*/rules":
+		var rules []interface{}
+		for _, rule := range server.rules[bindingGUID] {
+			rules = append(rules, rule)
+		}
+		server.writePage(w, r, rules)
+	case "POST bindings/*/rules":
+		var rule autoscaler.Rule
+		if !readJSON(w, r, &rule) {
+			return
+		}
+		for _, existing := range server.rules[bindingGUID] {
+			if existing.Type == rule.Type && existing.SubType == rule.SubType {
+				writeError(w, http.StatusConflict, "conflict", "A rule of type "+rule.Type+" already exists")
+				return
+			}
+		}
+		now := time.Now().UTC()
+		rule.GUID, rule.ServiceBindingGUID = NewGUID(), bindingGUID
+		rule.CreatedAt, rule.UpdatedAt = &now, &now
+		server.rules[bindingGUID] = append(server.rules[bindingGUID], rule)
+		writeJSON(w, http.StatusCreated, rule)
+	case "PUT bindings/*/rules/*", "DELETE bindings/*/rules/*":
+		rules := server.rules[bindingGUID]
+		for i := range rules {
+			if rules[i].GUID != path[3] {
+				continue
+			}
+			if r.Method == "DELETE" {
+				server.rules[bindingGUID] = append(rules[:i], rules[i+1:]...)
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			var rule autoscaler.Rule
+			if !readJSON(w, r, &rule) {
+				return
+			}
+			now := time.Now().UTC()
+			rule.GUID, rule.ServiceBindingGUID = rules[i].GUID, bindingGUID
+			rule.CreatedAt, rule.UpdatedAt = rules[i].CreatedAt, &now
+			rules[i] = rule
+			writeJSON(w, http.StatusOK, rule)
+			return
+		}
+		writeError(w, http.StatusNotFound, "not_found", "Unknown rule "+path[3])
+
+	case "GET bindings/*/scaling_events":
+		events := append([]autoscaler.ScalingDecision(nil), server.events[bindingGUID]...)
+		sort.SliceStable(events, func(i, j int) bool { return events[i].CreatedAt.After(*events[j].CreatedAt) })
+		var decisions []interface{}
+		for _, event := range events {
+			decisions = append(decisions, event)
+		}
+		server.writePage(w, r, decisions)
+
+	default:
+		writeError(w, http.StatusNotFound, "not_found", "Unknown path "+r.URL.Path)
+	}
+}
+
+// knownInstance returns true for the service instance of the server and the ones added to it
+func (server *Server) knownInstance(instanceGUID string) bool {
+	if instanceGUID == server.InstanceGUID {
+		return true
+	}
+	for _, resource := range server.cc["service_instances"] {
+		if resource.guid == instanceGUID {
+			return true
+		}
+	}
+	return false
+}
+
+func (server *Server) findBinding(bindingGUID string) *autoscaler.BindingResource {
+	for _, resource := range server.bindings {
+		if resource.GUID == bindingGUID {
+			return resource
+		}
+	}
+	return nil
+}
+
+// bindingResource returns a copy of a binding with its relationships filled in
+func (server *Server) bindingResource(resource *autoscaler.BindingResource) *autoscaler.BindingResource {
+	binding := *resource
+	binding.Relationships = autoscaler.Relationships{
+		Rules: append([]autoscaler.Rule(nil), server.rules[binding.GUID]...),
+	}
+	for _, event := range server.events[binding.GUID] {
+		if binding.Relationships.MostRecentEvent.CreatedAt == nil || event.CreatedAt.After(*binding.Relationships.MostRecentEvent.CreatedAt) {
+			binding.Relationships.MostRecentEvent = event
+		}
+	}
+	return &binding
+}
+
+// writePage writes the page of resources asked for through the page and per_page query parameters
+func (server *Server) writePage(w http.ResponseWriter, r *http.Request, resources []interface{}) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 {
+		perPage = server.PageSize
+	}
+	totalPages := (len(resources) + perPage - 1) / perPage
+
+	start, end := (page-1)*perPage, page*perPage
+	if start > len(resources) {
+		start = len(resources)
+	}
+	if end > len(resources) {
+		end = len(resources)
+	}
+
+	pagination := autoscaler.Pagination{
+		TotalResults: len(resources),
+		TotalPages:   totalPages,
+	}
+	if page < totalPages {
+		query := r.URL.Query()
+		query.Set("page", strconv.Itoa(page+1))
+		query.Set("per_page", strconv.Itoa(perPage))
+		pagination.Next = &autoscaler.Link{Href: r.URL.Path + "?" + query.Encode()}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"resources":  append([]interface{}{}, resources[start:end]...),
+		"pagination": pagination,
+	})
+}
+
+// pattern replaces the GUIDs of a path with *, so that it can be matched against a route
+func pattern(path []string) []string {
+	matched := make([]string, len(path))
+	for i, segment := range path {
+		if i%2 == 1 {
+			segment = "*"
+		}
+		matched[i] = segment
+	}
+	return matched
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, value interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(value); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, code, description string) {
+	writeJSON(w, status, autoscaler.ErrorResponse{Error: code, Description: description})
+}
@@ -0,0 +1,191 @@
+package autoscalertest_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"net/http"
+	"time"
+
+	"github.com/bijukunjummen/app-autoscaler-client"
+	"github.com/bijukunjummen/app-autoscaler-client/autoscalertest"
+)
+
+var _ = Describe("Fake Autoscaler Server", func() {
+	var server *autoscalertest.Server
+	var client autoscaler.Client
+	var binding *autoscaler.BindingResource
+
+	BeforeEach(func() {
+		server = autoscalertest.NewServer()
+		binding = server.AddBinding(autoscaler.Binding{
+			AppName:      "checkout",
+			MinInstances: 2,
+			MaxInstances: 5,
+			Enabled:      true,
+		})
+
+		var err error
+		client, err = autoscaler.NewClient(server.Config())
+		Ω(err).Should(BeNil())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Should serve the bindings of the service instance", func() {
+		server.AddBinding(autoscaler.Binding{AppName: "cart"})
+
+		serviceInstances, err := client.GetServiceBindings()
+
+		Ω(err).Should(BeNil())
+		Ω(len(serviceInstances.BindingResources)).Should(Equal(2))
+		Ω(serviceInstances.BindingResources[0].GUID).Should(Equal(binding.GUID))
+	})
+
+	It("Should keep the updates to a binding", func() {
+		_, err := client.UpdateBinding(binding.GUID, &autoscaler.Binding{MinInstances: 3, MaxInstances: 6})
+		Ω(err).Should(BeNil())
+
+		updated, err := client.GetBinding(binding.GUID)
+
+		Ω(err).Should(BeNil())
+		Ω(updated.AppName).Should(Equal("checkout"))
+		Ω(updated.MinInstances).Should(Equal(3))
+		Ω(updated.MaxInstances).Should(Equal(6))
+		Ω(updated.Enabled).Should(BeTrue())
+
+		_, err = client.UpdateBindingEnabled(binding.GUID, &autoscaler.Binding{MinInstances: 3, MaxInstances: 6})
+		Ω(err).Should(BeNil())
+		updated, err = client.GetBinding(binding.GUID)
+
+		Ω(err).Should(BeNil())
+		Ω(updated.Enabled).Should(BeFalse())
+	})
+
+	It("Should only update the limits sent", func() {
+		_, err := client.UpdateBinding(binding.GUID, &autoscaler.Binding{MaxInstances: 8})
+		Ω(err).Should(BeNil())
+
+		updated, err := client.GetBinding(binding.GUID)
+
+		Ω(err).Should(BeNil())
+		Ω(updated.MinInstances).Should(Equal(2))
+		Ω(updated.MaxInstances).Should(Equal(8))
+	})
+
+	It("Should create, update and delete scheduled limit changes", func() {
+		executesAt := time.Now().Add(time.Hour).UTC()
+		created, err := client.CreateScheduledLimitChange(binding.GUID, &autoscaler.ScheduledLimitChange{
+			ExecutesAt:   &executesAt,
+			MinInstances: 4,
+			MaxInstances: 8,
+			Enabled:      true,
+		})
+		Ω(err).Should(BeNil())
+		Ω(created.GUID).ShouldNot(BeEmpty())
+
+		created.MaxInstances = 10
+		_, err = client.UpdateScheduledLimitChange(binding.GUID, created.GUID, created)
+		Ω(err).Should(BeNil())
+		Ω(server.ScheduledLimitChanges(binding.GUID)[0].MaxInstances).Should(Equal(10))
+
+		Ω(client.DeleteScheduledLimitChange(binding.GUID, created.GUID)).Should(Succeed())
+		changes, err := client.GetScheduledLimitChanges(binding.GUID)
+		Ω(err).Should(BeNil())
+		Ω(changes).Should(BeEmpty())
+	})
+
+	It("Should create, update and delete rules", func() {
+		created, err := client.CreateRule(binding.GUID, &autoscaler.Rule{Type: "cpu", MinThreshold: 20, MaxThreshold: 80})
+		Ω(err).Should(BeNil())
+
+		_, err = client.CreateRule(binding.GUID, &autoscaler.Rule{Type: "cpu"})
+		Ω(autoscaler.IsConflict(err)).Should(BeTrue())
+
+		created.Enabled = true
+		_, err = client.UpdateRule(binding.GUID, created.GUID, created)
+		Ω(err).Should(BeNil())
+
+		updated, err := client.GetBinding(binding.GUID)
+		Ω(err).Should(BeNil())
+		Ω(len(updated.Relationships.Rules)).Should(Equal(1))
+		Ω(updated.Relationships.Rules[0].Enabled).Should(BeTrue())
+
+		Ω(client.DeleteRule(binding.GUID, created.GUID)).Should(Succeed())
+		Ω(server.Rules(binding.GUID)).Should(BeEmpty())
+	})
+
+	It("Should page through the scaling events", func() {
+		server.PageSize = 2
+		start := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+		for i := 0; i < 5; i++ {
+			createdAt := start.Add(time.Duration(i) * time.Hour)
+			server.AddScalingDecision(binding.GUID, autoscaler.ScalingDecision{CreatedAt: &createdAt, ScalingFactor: i})
+		}
+
+		decisions, err := client.GetScalingDecisions(binding.GUID, nil)
+
+		Ω(err).Should(BeNil())
+		Ω(len(decisions)).Should(Equal(5))
+		Ω(decisions[0].ScalingFactor).Should(Equal(0))
+		Ω(decisions[4].ScalingFactor).Should(Equal(4))
+	})
+
+	It("Should find the binding of an application through Cloud Controller", func() {
+		server.PageSize = 1
+		server.AddApp("acme", "production", "cart")
+		appGUID := server.AddApp("acme", "production", "checkout")
+		checkout := server.BindApp(appGUID, autoscaler.Binding{AppName: "checkout"})
+		server.BindApp(server.AddApp("acme", "staging", "checkout"), autoscaler.Binding{AppName: "checkout"})
+
+		found, err := client.FindBindingByAppName("acme", "production", "checkout")
+		Ω(err).Should(BeNil())
+		Ω(found.GUID).Should(Equal(checkout.GUID))
+
+		found, err = client.FindBindingByAppGUID(appGUID)
+		Ω(err).Should(BeNil())
+		Ω(found.GUID).Should(Equal(checkout.GUID))
+
+		_, err = client.FindBindingByAppName("acme", "production", "cart")
+		Ω(autoscaler.IsNotFound(err)).Should(BeTrue())
+		_, err = client.FindBindingByAppName("acme", "development", "checkout")
+		Ω(err).Should(MatchError(`No space "development" found`))
+	})
+
+	It("Should resolve the service instance by name through Cloud Controller", func() {
+		server.AddServiceInstance("acme", "production", "autoscaler")
+		config := server.Config()
+		config.InstanceGUID = ""
+
+		resolved, err := autoscaler.ConfigForServiceInstance(config, "acme", "production", "autoscaler")
+
+		Ω(err).Should(BeNil())
+		Ω(resolved.InstanceGUID).Should(Equal(server.InstanceGUID))
+		Ω(resolved.AutoscalerAPIUrl).Should(Equal(config.AutoscalerAPIUrl))
+		Ω(config.InstanceGUID).Should(BeEmpty())
+
+		_, err = autoscaler.ConfigForServiceInstance(config, "acme", "production", "unknown")
+		Ω(err).Should(MatchError(`No service instance "unknown" found`))
+	})
+
+	It("Should not find unknown bindings", func() {
+		_, err := client.GetBinding("unknown")
+
+		Ω(autoscaler.IsNotFound(err)).Should(BeTrue())
+	})
+	It("Should not find unknown paths", func() {
+		for _, path := range []string{"/api/instances", "/api/bindings", "/api/unknown"} {
+			request, err := http.NewRequest("GET", server.URL+path, nil)
+			Ω(err).Should(BeNil())
+			request.Header.Set("Authorization", "Bearer "+server.Token)
+
+			resp, err := http.DefaultClient.Do(request)
+
+			Ω(err).Should(BeNil())
+			resp.Body.Close()
+			Ω(resp.StatusCode).Should(Equal(http.StatusNotFound), path)
+		}
+	})
+})
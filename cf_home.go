@@ -0,0 +1,62 @@
+package autoscaler
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// cfCLIConfig is the part of the cf CLI config.json used to reuse its login
+type cfCLIConfig struct {
+	Target               string `json:"Target"`
+	AccessToken          string `json:"AccessToken"`
+	RefreshToken         string `json:"RefreshToken"`
+	SSLDisabled          bool   `json:"SSLDisabled"`
+	UAAOAuthClient       string `json:"UAAOAuthClient"`
+	UAAOAuthClientSecret string `json:"UAAOAuthClientSecret"`
+}
+
+// CFConfigFromCFHome reuses the login of the cf CLI, read from $CF_HOME/.cf/config.json, or ~/.cf/config.json
+// if CF_HOME is not set. The error satisfies os.IsNotExist when there is no config.json, neither CF_HOME nor HOME
+// being set included.
+func CFConfigFromCFHome() (*CFConfig, error) {
+	home := os.Getenv("CF_HOME")
+	if home == "" {
+		home = os.Getenv("HOME")
+	}
+	if home == "" {
+		return nil, &os.PathError{Op: "open", Path: "$CF_HOME/.cf/config.json", Err: os.ErrNotExist}
+	}
+	return CFConfigFromFile(filepath.Join(home, ".cf", "config.json"))
+}
+
+// CFConfigFromFile reuses the login of the cf CLI from its config.json: the target API, the SSL settings,
+// and the access and refresh tokens, the access token being refreshed through UAA once expired
+func CFConfigFromFile(path string) (*CFConfig, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var cliConfig cfCLIConfig
+	if err = json.NewDecoder(file).Decode(&cliConfig); err != nil {
+		return nil, err
+	}
+	if cliConfig.Target == "" {
+		return nil, errors.New("No API targeted in " + path)
+	}
+	if cliConfig.AccessToken == "" && cliConfig.RefreshToken == "" {
+		return nil, errors.New("Not logged in according to " + path)
+	}
+
+	return &CFConfig{
+		CCApiURL:          cliConfig.Target,
+		AccessToken:       cliConfig.AccessToken,
+		RefreshToken:      cliConfig.RefreshToken,
+		ClientID:          cliConfig.UAAOAuthClient,
+		ClientSecret:      cliConfig.UAAOAuthClientSecret,
+		SkipSslValidation: cliConfig.SSLDisabled,
+	}, nil
+}
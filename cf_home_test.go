@@ -0,0 +1,150 @@
+package autoscaler_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/bijukunjummen/app-autoscaler-client"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("cf CLI login", func() {
+	var server *ghttp.Server
+	var home string
+
+	jwt := func(expiry time.Time) string {
+		claims := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp": %d}`, expiry.Unix())))
+		return "eyJhbGciOiJSUzI1NiJ9." + claims + ".c2lnbmF0dXJl"
+	}
+	writeCFConfig := func(accessToken string) {
+		Ω(os.MkdirAll(filepath.Join(home, ".cf"), 0700)).Should(Succeed())
+		Ω(ioutil.WriteFile(filepath.Join(home, ".cf", "config.json"), []byte(fmt.Sprintf(`{
+			"ConfigVersion": 3,
+			"Target": "%s",
+			"AccessToken": "bearer %s",
+			"RefreshToken": "refresh-token",
+			"SSLDisabled": true,
+			"UAAOAuthClient": "cf",
+			"UAAOAuthClientSecret": ""
+		}`, server.URL(), accessToken)), 0600)).Should(Succeed())
+	}
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v2/info"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, Endpoint{
+					AuthorizationEndpoint: server.URL(),
+					TokenEndpoint:         server.URL(),
+				}),
+			),
+		)
+		var err error
+		home, err = ioutil.TempDir("", "cf-home")
+		Ω(err).Should(BeNil())
+		os.Setenv("CF_HOME", home)
+	})
+
+	AfterEach(func() {
+		os.Unsetenv("CF_HOME")
+		os.RemoveAll(home)
+		server.Close()
+	})
+
+	It("Should read the target and tokens of the cf CLI", func() {
+		accessToken := jwt(time.Now().Add(time.Hour))
+		writeCFConfig(accessToken)
+
+		config, err := CFConfigFromCFHome()
+
+		Ω(err).Should(BeNil())
+		Ω(config.CCApiURL).Should(Equal(server.URL()))
+		Ω(config.SkipSslValidation).Should(BeTrue())
+		Ω(config.RefreshToken).Should(Equal("refresh-token"))
+
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v2/organizations"),
+				ghttp.VerifyHeaderKV("Authorization", "Bearer "+accessToken),
+			),
+		)
+		client, err := NewUAAClient(config)
+		Ω(err).Should(BeNil())
+		request, err := client.NewCCRequest("GET", "/v2/organizations", nil)
+		Ω(err).Should(BeNil())
+		_, err = client.Do(request)
+		Ω(err).Should(BeNil())
+		Ω(len(server.ReceivedRequests())).Should(Equal(2))
+	})
+
+	It("Should refresh the expired access token through UAA", func() {
+		writeCFConfig(jwt(time.Now().Add(-time.Minute)))
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/oauth/token"),
+				ghttp.VerifyBasicAuth("cf", ""),
+				ghttp.VerifyForm(url.Values{"grant_type": {"refresh_token"}, "refresh_token": {"refresh-token"}}),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, AccessToken{Token: "fresh-token", ExpiresIn: 3600}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v2/organizations"),
+				ghttp.VerifyHeaderKV("Authorization", "Bearer fresh-token"),
+			),
+		)
+
+		config, err := CFConfigFromCFHome()
+		Ω(err).Should(BeNil())
+		client, err := NewUAAClient(config)
+		Ω(err).Should(BeNil())
+		request, err := client.NewCCRequest("GET", "/v2/organizations", nil)
+		Ω(err).Should(BeNil())
+		_, err = client.Do(request)
+
+		Ω(err).Should(BeNil())
+		Ω(len(server.ReceivedRequests())).Should(Equal(3))
+	})
+
+	It("Should find the config of the cf CLI in HOME without CF_HOME", func() {
+		writeCFConfig(jwt(time.Now().Add(time.Hour)))
+		userHome := os.Getenv("HOME")
+		defer os.Setenv("HOME", userHome)
+		os.Unsetenv("CF_HOME")
+		os.Setenv("HOME", home)
+
+		config, err := CFConfigFromCFHome()
+
+		Ω(err).Should(BeNil())
+		Ω(config.CCApiURL).Should(Equal(server.URL()))
+	})
+
+	It("Should report a missing config when neither CF_HOME nor HOME is set", func() {
+		userHome := os.Getenv("HOME")
+		defer os.Setenv("HOME", userHome)
+		os.Unsetenv("CF_HOME")
+		os.Unsetenv("HOME")
+
+		_, err := CFConfigFromCFHome()
+
+		Ω(os.IsNotExist(err)).Should(BeTrue())
+	})
+
+	It("Should fail when the cf CLI is not logged in", func() {
+		Ω(ioutil.WriteFile(filepath.Join(home, "config.json"), []byte(`{"Target": "https://api.example.com"}`), 0600)).Should(Succeed())
+
+		_, err := CFConfigFromFile(filepath.Join(home, "config.json"))
+		Ω(err).Should(MatchError(ContainSubstring("Not logged in")))
+
+		_, err = CFConfigFromCFHome()
+		Ω(os.IsNotExist(err)).Should(BeTrue())
+	})
+})
@@ -0,0 +1,154 @@
+package autoscaler
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"golang.org/x/net/context"
+)
+
+// ccResource is a resource listed by the Cloud Controller v2 API, only the entity fields used by the client are kept
+type ccResource struct {
+	Metadata struct {
+		GUID string `json:"guid"`
+	} `json:"metadata"`
+	Entity struct {
+		Name                string `json:"name"`
+		OrganizationGUID    string `json:"organization_guid"`
+		SpaceGUID           string `json:"space_guid"`
+		AppGUID             string `json:"app_guid"`
+		ServiceInstanceGUID string `json:"service_instance_guid"`
+		DashboardURL        string `json:"dashboard_url"`
+	} `json:"entity"`
+}
+
+// FindBindingByAppName ...
+func (client *DefaultClient) FindBindingByAppName(org, space, app string) (*BindingResource, error) {
+	return client.FindBindingByAppNameContext(context.Background(), org, space, app)
+}
+
+// FindBindingByAppNameContext resolves the GUID of the application through Cloud Controller,
+// then finds its binding to the service instance of the client
+func (client *DefaultClient) FindBindingByAppNameContext(ctx context.Context, org, space, app string) (*BindingResource, error) {
+	spaceResource, err := client.findSpace(ctx, org, space)
+	if err != nil {
+		return nil, err
+	}
+	appResource, err := client.findCCResource(ctx, "app", "/v2/spaces/"+spaceResource.Metadata.GUID+"/apps", app)
+	if err != nil {
+		return nil, err
+	}
+	return client.FindBindingByAppGUIDContext(ctx, appResource.Metadata.GUID)
+}
+
+// FindBindingByAppGUID ...
+func (client *DefaultClient) FindBindingByAppGUID(appGUID string) (*BindingResource, error) {
+	return client.FindBindingByAppGUIDContext(context.Background(), appGUID)
+}
+
+// FindBindingByAppGUIDContext finds the Cloud Controller service binding of the application to the service instance
+// of the client, then gets that binding from the Autoscaler API
+func (client *DefaultClient) FindBindingByAppGUIDContext(ctx context.Context, appGUID string) (*BindingResource, error) {
+	var serviceBindings []ccResource
+	query := url.Values{"q": {"service_instance_guid:" + client.config.InstanceGUID}}
+	if err := client.listCCResources(ctx, "/v2/apps/"+appGUID+"/service_bindings", query, &serviceBindings); err != nil {
+		return nil, err
+	}
+	if len(serviceBindings) == 0 {
+		return nil, &NotFoundError{Resource: "binding of app", Name: appGUID}
+	}
+	return client.GetBindingContext(ctx, serviceBindings[0].Metadata.GUID)
+}
+
+// ConfigForServiceInstance ...
+func ConfigForServiceInstance(config *Config, org, space, instanceName string) (*Config, error) {
+	return ConfigForServiceInstanceWithContext(context.Background(), config, org, space, instanceName)
+}
+
+// ConfigForServiceInstanceWithContext returns a copy of the config with the InstanceGUID of the autoscaler service
+// instance of the given name in the org and space, as resolved through Cloud Controller with the CFConfig of the config.
+// The AutoscalerAPIUrl is derived from the dashboard url of the instance when not set.
+func ConfigForServiceInstanceWithContext(ctx context.Context, config *Config, org, space, instanceName string) (*Config, error) {
+	oauthWrapper, err := NewUAAClientWithContext(ctx, config.CFConfig)
+	if err != nil {
+		return nil, err
+	}
+	client := &DefaultClient{
+		httpClient: oauthWrapper,
+		config:     config,
+	}
+
+	spaceResource, err := client.findSpace(ctx, org, space)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := client.findCCResource(ctx, "service instance", "/v2/spaces/"+spaceResource.Metadata.GUID+"/service_instances", instanceName)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := *config
+	resolved.InstanceGUID = instance.Metadata.GUID
+	if resolved.AutoscalerAPIUrl == "" {
+		resolved.AutoscalerAPIUrl = apiURLFromDashboard(instance.Entity.DashboardURL)
+	}
+	return &resolved, nil
+}
+
+// findSpace finds the Cloud Controller space given its name and the name of its org
+func (client *DefaultClient) findSpace(ctx context.Context, org, space string) (*ccResource, error) {
+	orgResource, err := client.findCCResource(ctx, "organization", "/v2/organizations", org)
+	if err != nil {
+		return nil, err
+	}
+	return client.findCCResource(ctx, "space", "/v2/organizations/"+orgResource.Metadata.GUID+"/spaces", space)
+}
+
+// findCCResource finds the Cloud Controller resource of the list at path with the given name
+func (client *DefaultClient) findCCResource(ctx context.Context, kind, path, name string) (*ccResource, error) {
+	var resources []ccResource
+	if err := client.listCCResources(ctx, path, url.Values{"q": {"name:" + name}}, &resources); err != nil {
+		return nil, err
+	}
+	if len(resources) == 0 {
+		return nil, &NotFoundError{Resource: kind, Name: name}
+	}
+	return &resources[0], nil
+}
+
+// getCCResource gets the Cloud Controller resource at path
+func (client *DefaultClient) getCCResource(ctx context.Context, path string) (*ccResource, error) {
+	request, err := client.httpClient.NewCCRequestWithContext(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, newAPIError(resp)
+	}
+
+	var resource ccResource
+
+	decoder := json.NewDecoder(resp.Body)
+	if err = decoder.Decode(&resource); err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// listCCResources lists every page of the Cloud Controller resources at path, following their next_url
+func (client *DefaultClient) listCCResources(ctx context.Context, path string, query url.Values, out *[]ccResource) error {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	request, err := client.httpClient.NewCCRequestWithContext(ctx, "GET", path, nil)
+	if err != nil {
+		return err
+	}
+	return client.listAll(ctx, client.config.CFConfig.CCApiURL, request.URL.String(), nil, out)
+}
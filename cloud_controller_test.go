@@ -0,0 +1,100 @@
+package autoscaler_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"net/http"
+
+	. "github.com/bijukunjummen/app-autoscaler-client"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Bindings lookup through Cloud Controller", func() {
+	var server *ghttp.Server
+	var client Client
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v2/info"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, Endpoint{
+					AuthorizationEndpoint: server.URL(),
+					TokenEndpoint:         server.URL(),
+				}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/oauth/token"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, AccessToken{
+					Token: "test-token",
+				}),
+			),
+		)
+		var err error
+		client, err = NewClient(&Config{
+			CFConfig: &CFConfig{
+				CCApiURL:          server.URL(),
+				Username:          "user",
+				Password:          "pwd",
+				SkipSslValidation: true,
+			},
+			AutoscalerAPIUrl: server.URL() + "/api",
+			InstanceGUID:     "instanceid",
+		})
+		Ω(err).Should(BeNil())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Should resolve the org, space and app to find the binding", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v2/organizations", "q=name%3Aacme"),
+				ghttp.VerifyHeaderKV("Authorization", "Bearer test-token"),
+				ghttp.RespondWith(http.StatusOK, `{"resources": [{"metadata": {"guid": "org-1"}, "entity": {"name": "acme"}}]}`),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v2/organizations/org-1/spaces", "q=name%3Aproduction"),
+				ghttp.RespondWith(http.StatusOK, `{"resources": [{"metadata": {"guid": "space-1"}, "entity": {"name": "production"}}]}`),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v2/spaces/space-1/apps", "q=name%3Acheckout"),
+				ghttp.RespondWith(http.StatusOK, `{"resources": [{"metadata": {"guid": "app-1"}, "entity": {"name": "checkout"}}]}`),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v2/apps/app-1/service_bindings", "q=service_instance_guid%3Ainstanceid"),
+				ghttp.RespondWith(http.StatusOK, `{"next_url": "/v2/apps/app-1/service_bindings?page=2", "resources": []}`),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v2/apps/app-1/service_bindings", "page=2"),
+				ghttp.RespondWith(http.StatusOK, `{"next_url": null, "resources": [{"metadata": {"guid": "binding-1"}, "entity": {"app_guid": "app-1"}}]}`),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/bindings/binding-1"),
+				ghttp.RespondWith(http.StatusOK, `{"guid": "binding-1", "app_name": "checkout"}`),
+			),
+		)
+
+		binding, err := client.FindBindingByAppName("acme", "production", "checkout")
+
+		Ω(err).Should(BeNil())
+		Ω(binding.GUID).Should(Equal("binding-1"))
+	})
+
+	It("Should not find an app without a binding to the service instance", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v2/apps/app-1/service_bindings"),
+				ghttp.RespondWith(http.StatusOK, `{"resources": []}`),
+			),
+		)
+
+		_, err := client.FindBindingByAppGUID("app-1")
+
+		Ω(IsNotFound(err)).Should(BeTrue())
+		Ω(err).Should(MatchError(`No binding of app "app-1" found`))
+	})
+})
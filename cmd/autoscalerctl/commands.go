@@ -0,0 +1,500 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bijukunjummen/app-autoscaler-client"
+)
+
+// command is an action on a resource, like "bindings list"
+type command struct {
+	usage string
+	run   func(client autoscaler.Client, args []string, out io.Writer) error
+}
+
+var commands = map[string]map[string]command{
+	"bindings": {
+		"list":    {"", listBindings},
+		"get":     {"BINDING_GUID", getBinding},
+		"find":    {"--org ORG --space SPACE --app APP | --app-guid APP_GUID", findBinding},
+		"update":  {"BINDING_GUID [--min N] [--max N] [--enabled=true|false]", updateBinding},
+		"enable":  {"BINDING_GUID", enableBinding(true)},
+		"disable": {"BINDING_GUID", enableBinding(false)},
+	},
+	"schedules": {
+		"list":   {"BINDING_GUID", listSchedules},
+		"create": {"BINDING_GUID (--executes-at TIME [--recurrence DAYS] | --cron EXPRESSION [--timezone ZONE]) --min N --max N [--enabled=true|false]", createSchedule},
+		"update": {"BINDING_GUID SCHEDULE_GUID [--executes-at TIME] [--min N] [--max N] [--recurrence DAYS] [--enabled=true|false]", updateSchedule},
+		"delete": {"BINDING_GUID SCHEDULE_GUID", deleteSchedule},
+	},
+	"rules": {
+		"list":   {"BINDING_GUID", listRules},
+		"create": {"BINDING_GUID --type TYPE [--sub-type SUB_TYPE] --min-threshold N --max-threshold N [--enabled=true|false]", createRule},
+		"update": {"BINDING_GUID RULE_GUID [--min-threshold N] [--max-threshold N] [--enabled=true|false]", updateRule},
+		"delete": {"BINDING_GUID RULE_GUID", deleteRule},
+	},
+	"events": {
+		"list": {"BINDING_GUID [--since TIME] [--until TIME] [--max N]", listEvents},
+	},
+	"policy": {
+		"plan":  {"BINDING_GUID --file POLICY_FILE", planPolicy(false)},
+		"apply": {"BINDING_GUID --file POLICY_FILE", planPolicy(true)},
+	},
+}
+
+func listBindings(client autoscaler.Client, args []string, out io.Writer) error {
+	if _, err := parse(flag.NewFlagSet("bindings list", flag.ContinueOnError), args); err != nil {
+		return err
+	}
+	serviceInstances, err := client.GetServiceBindings()
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "GUID\tAPP\tMIN\tMAX\tEXPECTED\tENABLED")
+	for _, binding := range serviceInstances.BindingResources {
+		fmt.Fprintf(writer, "%s\t%s\t%d\t%d\t%d\t%t\n", binding.GUID, binding.AppName,
+			binding.MinInstances, binding.MaxInstances, binding.ExpectedInstanceCount, binding.Enabled)
+	}
+	return writer.Flush()
+}
+
+func getBinding(client autoscaler.Client, args []string, out io.Writer) error {
+	positional, err := parse(flag.NewFlagSet("bindings get", flag.ContinueOnError), args, "BINDING_GUID")
+	if err != nil {
+		return err
+	}
+	binding, err := client.GetBinding(positional[0])
+	if err != nil {
+		return err
+	}
+	return printJSON(out, binding)
+}
+
+func findBinding(client autoscaler.Client, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("bindings find", flag.ContinueOnError)
+	org := flags.String("org", "", "org of the application")
+	space := flags.String("space", "", "space of the application")
+	app := flags.String("app", "", "name of the application")
+	appGUID := flags.String("app-guid", "", "GUID of the application, instead of its org, space and name")
+	if _, err := parse(flags, args); err != nil {
+		return err
+	}
+
+	var binding *autoscaler.BindingResource
+	var err error
+	switch {
+	case *appGUID != "":
+		binding, err = client.FindBindingByAppGUID(*appGUID)
+	case *org != "" && *space != "" && *app != "":
+		binding, err = client.FindBindingByAppName(*org, *space, *app)
+	default:
+		return fmt.Errorf("--org, --space and --app, or --app-guid are required")
+	}
+	if err != nil {
+		return err
+	}
+	return printJSON(out, binding)
+}
+
+func updateBinding(client autoscaler.Client, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("bindings update", flag.ContinueOnError)
+	min := flags.Int("min", 0, "minimum number of instances")
+	max := flags.Int("max", 0, "maximum number of instances")
+	enabled := flags.Bool("enabled", false, "whether autoscaling is enabled")
+	positional, err := parse(flags, args, "BINDING_GUID")
+	if err != nil {
+		return err
+	}
+
+	binding, err := client.GetBinding(positional[0])
+	if err != nil {
+		return err
+	}
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "min":
+			binding.MinInstances = *min
+		case "max":
+			binding.MaxInstances = *max
+		case "enabled":
+			binding.Enabled = *enabled
+		}
+	})
+
+	updated, err := client.UpdateBindingEnabled(positional[0], &binding.Binding)
+	if err != nil {
+		return err
+	}
+	return printJSON(out, updated)
+}
+
+func enableBinding(enabled bool) func(client autoscaler.Client, args []string, out io.Writer) error {
+	return func(client autoscaler.Client, args []string, out io.Writer) error {
+		return updateBinding(client, append(args, fmt.Sprintf("--enabled=%t", enabled)), out)
+	}
+}
+
+func listSchedules(client autoscaler.Client, args []string, out io.Writer) error {
+	positional, err := parse(flag.NewFlagSet("schedules list", flag.ContinueOnError), args, "BINDING_GUID")
+	if err != nil {
+		return err
+	}
+	changes, err := client.GetScheduledLimitChanges(positional[0])
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "GUID\tEXECUTES_AT\tMIN\tMAX\tRECURRENCE\tENABLED")
+	for _, change := range changes {
+		fmt.Fprintf(writer, "%s\t%s\t%d\t%d\t%v\t%t\n", change.GUID, formatTime(change.ExecutesAt),
+			change.MinInstances, change.MaxInstances, change.Recurrence, change.Enabled)
+	}
+	return writer.Flush()
+}
+
+// scheduleFlags registers the flags describing a scheduled limit change, the returned func applies the flags set
+func scheduleFlags(flags *flag.FlagSet) func(change *autoscaler.ScheduledLimitChange) error {
+	executesAt := flags.String("executes-at", "", "time the change executes at, in RFC3339 format")
+	min := flags.Int("min", 0, "minimum number of instances")
+	max := flags.Int("max", 0, "maximum number of instances")
+	recurrence := flags.String("recurrence", "", "days of the week the change recurs on, like mon,wed,fri or weekdays")
+	enabled := flags.Bool("enabled", true, "whether the change is enabled")
+
+	return func(change *autoscaler.ScheduledLimitChange) error {
+		var err error
+		flags.Visit(func(f *flag.Flag) {
+			if err != nil {
+				return
+			}
+			switch f.Name {
+			case "executes-at":
+				var at time.Time
+				if at, err = time.Parse(time.RFC3339, *executesAt); err == nil {
+					change.ExecutesAt = &at
+				}
+			case "min":
+				change.MinInstances = *min
+			case "max":
+				change.MaxInstances = *max
+			case "recurrence":
+				change.Recurrence, err = autoscaler.ParseRecurrence(*recurrence)
+			case "enabled":
+				change.Enabled = *enabled
+			}
+		})
+		return err
+	}
+}
+
+func createSchedule(client autoscaler.Client, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("schedules create", flag.ContinueOnError)
+	apply := scheduleFlags(flags)
+	cron := flags.String("cron", "", "cron expression like \"0 9 * * mon-fri\" the changes execute at, instead of --executes-at and --recurrence")
+	timezone := flags.String("timezone", "UTC", "time zone of the cron expression")
+	positional, err := parse(flags, args, "BINDING_GUID")
+	if err != nil {
+		return err
+	}
+
+	change := &autoscaler.ScheduledLimitChange{Enabled: true}
+	if err = apply(change); err != nil {
+		return err
+	}
+	if *cron != "" {
+		return createCronSchedules(client, positional[0], *cron, *timezone, change, out)
+	}
+	if change.ExecutesAt == nil {
+		return fmt.Errorf("--executes-at or --cron is required")
+	}
+
+	created, err := client.CreateScheduledLimitChange(positional[0], change)
+	if err != nil {
+		return err
+	}
+	return printJSON(out, created)
+}
+
+func createCronSchedules(client autoscaler.Client, bindingGUID, cron, timezone string, template *autoscaler.ScheduledLimitChange, out io.Writer) error {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return err
+	}
+	changes, err := autoscaler.ScheduledLimitChangesFromCron(cron, location, template.MinInstances, template.MaxInstances, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var created []*autoscaler.ScheduledLimitChange
+	for i := range changes {
+		changes[i].Enabled = template.Enabled
+		change, err := client.CreateScheduledLimitChange(bindingGUID, &changes[i])
+		if err != nil {
+			printJSON(out, created)
+			return fmt.Errorf("Could not create the scheduled limit change at %s, %d of %d created: %v",
+				formatTime(changes[i].ExecutesAt), len(created), len(changes), err)
+		}
+		created = append(created, change)
+	}
+	return printJSON(out, created)
+}
+
+func updateSchedule(client autoscaler.Client, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("schedules update", flag.ContinueOnError)
+	apply := scheduleFlags(flags)
+	positional, err := parse(flags, args, "BINDING_GUID", "SCHEDULE_GUID")
+	if err != nil {
+		return err
+	}
+
+	changes, err := client.GetScheduledLimitChanges(positional[0])
+	if err != nil {
+		return err
+	}
+	var change *autoscaler.ScheduledLimitChange
+	for i := range changes {
+		if changes[i].GUID == positional[1] {
+			change = &changes[i]
+		}
+	}
+	if change == nil {
+		return fmt.Errorf("Scheduled limit change %s not found for binding %s", positional[1], positional[0])
+	}
+	if err = apply(change); err != nil {
+		return err
+	}
+
+	updated, err := client.UpdateScheduledLimitChange(positional[0], positional[1], change)
+	if err != nil {
+		return err
+	}
+	return printJSON(out, updated)
+}
+
+func deleteSchedule(client autoscaler.Client, args []string, out io.Writer) error {
+	positional, err := parse(flag.NewFlagSet("schedules delete", flag.ContinueOnError), args, "BINDING_GUID", "SCHEDULE_GUID")
+	if err != nil {
+		return err
+	}
+	return client.DeleteScheduledLimitChange(positional[0], positional[1])
+}
+
+func listRules(client autoscaler.Client, args []string, out io.Writer) error {
+	positional, err := parse(flag.NewFlagSet("rules list", flag.ContinueOnError), args, "BINDING_GUID")
+	if err != nil {
+		return err
+	}
+	rules, err := client.GetRules(positional[0])
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "GUID\tTYPE\tSUB_TYPE\tMIN_THRESHOLD\tMAX_THRESHOLD\tENABLED")
+	for _, rule := range rules {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%d\t%d\t%t\n", rule.GUID, rule.Type, rule.SubType,
+			rule.MinThreshold, rule.MaxThreshold, rule.Enabled)
+	}
+	return writer.Flush()
+}
+
+// ruleFlags registers the flags describing a rule, the returned func applies the flags set
+func ruleFlags(flags *flag.FlagSet) func(rule *autoscaler.Rule) {
+	ruleType := flags.String("type", "", "type of the rule, like cpu, memory, http_throughput or http_latency")
+	subType := flags.String("sub-type", "", "sub type of the rule, like avg_95th for http_latency")
+	minThreshold := flags.Int("min-threshold", 0, "threshold under which the app is scaled down")
+	maxThreshold := flags.Int("max-threshold", 0, "threshold over which the app is scaled up")
+	enabled := flags.Bool("enabled", true, "whether the rule is enabled")
+
+	return func(rule *autoscaler.Rule) {
+		flags.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "type":
+				rule.Type = *ruleType
+			case "sub-type":
+				rule.SubType = *subType
+			case "min-threshold":
+				rule.MinThreshold = *minThreshold
+			case "max-threshold":
+				rule.MaxThreshold = *maxThreshold
+			case "enabled":
+				rule.Enabled = *enabled
+			}
+		})
+	}
+}
+
+func createRule(client autoscaler.Client, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("rules create", flag.ContinueOnError)
+	apply := ruleFlags(flags)
+	positional, err := parse(flags, args, "BINDING_GUID")
+	if err != nil {
+		return err
+	}
+
+	rule := &autoscaler.Rule{Enabled: true}
+	apply(rule)
+	if rule.Type == "" {
+		return fmt.Errorf("--type is required")
+	}
+
+	created, err := client.CreateRule(positional[0], rule)
+	if err != nil {
+		return err
+	}
+	return printJSON(out, created)
+}
+
+func updateRule(client autoscaler.Client, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("rules update", flag.ContinueOnError)
+	apply := ruleFlags(flags)
+	positional, err := parse(flags, args, "BINDING_GUID", "RULE_GUID")
+	if err != nil {
+		return err
+	}
+
+	rules, err := client.GetRules(positional[0])
+	if err != nil {
+		return err
+	}
+	var rule *autoscaler.Rule
+	for i := range rules {
+		if rules[i].GUID == positional[1] {
+			rule = &rules[i]
+		}
+	}
+	if rule == nil {
+		return fmt.Errorf("Rule %s not found for binding %s", positional[1], positional[0])
+	}
+	apply(rule)
+
+	updated, err := client.UpdateRule(positional[0], positional[1], rule)
+	if err != nil {
+		return err
+	}
+	return printJSON(out, updated)
+}
+
+func deleteRule(client autoscaler.Client, args []string, out io.Writer) error {
+	positional, err := parse(flag.NewFlagSet("rules delete", flag.ContinueOnError), args, "BINDING_GUID", "RULE_GUID")
+	if err != nil {
+		return err
+	}
+	return client.DeleteRule(positional[0], positional[1])
+}
+
+func listEvents(client autoscaler.Client, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("events list", flag.ContinueOnError)
+	since := flags.String("since", "", "only events created at or after this time, in RFC3339 format")
+	until := flags.String("until", "", "only events created at or before this time, in RFC3339 format")
+	max := flags.Int("max", 0, "only the most recent events, up to this number")
+	positional, err := parse(flags, args, "BINDING_GUID")
+	if err != nil {
+		return err
+	}
+
+	filter := &autoscaler.ScalingDecisionsFilter{MaxResults: *max}
+	if *since != "" {
+		if filter.Since, err = time.Parse(time.RFC3339, *since); err != nil {
+			return err
+		}
+	}
+	if *until != "" {
+		if filter.Until, err = time.Parse(time.RFC3339, *until); err != nil {
+			return err
+		}
+	}
+
+	decisions, err := client.GetScalingDecisions(positional[0], filter)
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "CREATED_AT\tSCALING_FACTOR\tDESCRIPTION")
+	for _, decision := range decisions {
+		description := strings.Replace(decision.Description, "\n", " ", -1)
+		fmt.Fprintf(writer, "%s\t%d\t%s\n", formatTime(decision.CreatedAt), decision.ScalingFactor, description)
+	}
+	return writer.Flush()
+}
+
+func planPolicy(apply bool) func(client autoscaler.Client, args []string, out io.Writer) error {
+	return func(client autoscaler.Client, args []string, out io.Writer) error {
+		flags := flag.NewFlagSet("policy", flag.ContinueOnError)
+		file := flags.String("file", "", "YAML or JSON file holding the policy")
+		positional, err := parse(flags, args, "BINDING_GUID")
+		if err != nil {
+			return err
+		}
+		if *file == "" {
+			return fmt.Errorf("--file is required")
+		}
+
+		policy, err := autoscaler.LoadPolicyFile(*file)
+		if err != nil {
+			return err
+		}
+		plan, err := autoscaler.PlanPolicy(client, positional[0], policy)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(out, plan)
+
+		if !apply || plan.Empty() {
+			return nil
+		}
+		if err = plan.Apply(client); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Applied %d changes\n", len(plan.Changes))
+		return nil
+	}
+}
+
+// parse parses the flags of a command, which may come before or after its positional arguments
+func parse(flags *flag.FlagSet, args []string, names ...string) ([]string, error) {
+	flags.SetOutput(ioutil.Discard)
+
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	if len(positional) != len(names) {
+		return nil, fmt.Errorf("Expected arguments: %s", strings.Join(names, " "))
+	}
+	return positional, nil
+}
+
+func printJSON(out io.Writer, value interface{}) error {
+	encoded, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "%s\n", encoded)
+	return err
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
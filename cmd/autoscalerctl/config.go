@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bijukunjummen/app-autoscaler-client"
+)
+
+// settings holds everything needed to connect to the Autoscaler API, it is also the layout of the config file
+type settings struct {
+	API               string `json:"api"`
+	Username          string `json:"username"`
+	Password          string `json:"password"`
+	ClientID          string `json:"client_id"`
+	ClientSecret      string `json:"client_secret"`
+	SkipSslValidation bool   `json:"skip_ssl_validation"`
+	CACertFile        string `json:"ca_cert_file"`
+	ClientCertFile    string `json:"client_cert_file"`
+	ClientKeyFile     string `json:"client_key_file"`
+	ProxyURL          string `json:"proxy_url"`
+	NoProxy           string `json:"no_proxy"`
+	AutoscalerAPIUrl  string `json:"autoscaler_api_url"`
+	InstanceGUID      string `json:"instance_guid"`
+	Org               string `json:"org"`
+	Space             string `json:"space"`
+	InstanceName      string `json:"instance_name"`
+}
+
+// bind registers the global flags, writing into the settings
+func (s *settings) bind(flags *flag.FlagSet) {
+	flags.StringVar(&s.API, "api", "", "Cloud Controller API url [$CF_API]")
+	flags.StringVar(&s.Username, "username", "", "user to authenticate as [$CF_USERNAME]")
+	flags.StringVar(&s.Password, "password", "", "password of the user [$CF_PASSWORD]")
+	flags.StringVar(&s.ClientID, "client-id", "", "client to authenticate as, instead of a user [$CF_CLIENT_ID]")
+	flags.StringVar(&s.ClientSecret, "client-secret", "", "secret of the client [$CF_CLIENT_SECRET]")
+	flags.BoolVar(&s.SkipSslValidation, "skip-ssl-validation", false, "skip verification of the API certificates [$CF_SKIP_SSL_VALIDATION]")
+	flags.StringVar(&s.CACertFile, "ca-cert", "", "PEM file of CA certificates to trust [$CF_CA_CERT_FILE]")
+	flags.StringVar(&s.ClientCertFile, "client-cert", "", "PEM file of the client certificate for mutual TLS [$CF_CLIENT_CERT_FILE]")
+	flags.StringVar(&s.ClientKeyFile, "client-key", "", "PEM file of the key of the client certificate [$CF_CLIENT_KEY_FILE]")
+	flags.StringVar(&s.ProxyURL, "proxy", "", "proxy url every call goes through [$CF_PROXY_URL]")
+	flags.StringVar(&s.NoProxy, "no-proxy", "", "comma separated hosts not to reach through the proxy [$CF_NO_PROXY]")
+	flags.StringVar(&s.AutoscalerAPIUrl, "autoscaler-api", "", "Autoscaler API url, like https://autoscale.example.com/api, discovered if not set [$AUTOSCALER_API_URL]")
+	flags.StringVar(&s.InstanceGUID, "instance-guid", "", "GUID of the autoscaler service instance [$AUTOSCALER_INSTANCE_GUID]")
+	flags.StringVar(&s.Org, "org", "", "org of the autoscaler service instance, when given by name [$CF_ORG]")
+	flags.StringVar(&s.Space, "space", "", "space of the autoscaler service instance, when given by name [$CF_SPACE]")
+	flags.StringVar(&s.InstanceName, "instance-name", "", "name of the autoscaler service instance, instead of its GUID [$AUTOSCALER_INSTANCE_NAME]")
+}
+
+// loadSettings reads the settings from the config file, then the environment and then the flags set,
+// each overriding the previous one. The environment is read like autoscaler.ConfigFromEnv does, VCAP variables
+// included, and for the service instance given by name from CF_ORG, CF_SPACE and AUTOSCALER_INSTANCE_NAME.
+func loadSettings(path string, flags *flag.FlagSet, fromFlags *settings) (*settings, error) {
+	s := &settings{}
+
+	if path == "" {
+		path = os.Getenv("AUTOSCALERCTL_CONFIG")
+	}
+	if path == "" {
+		if home := os.Getenv("HOME"); home != "" {
+			path = filepath.Join(home, ".autoscalerctl.json")
+			if _, err := os.Stat(path); err != nil {
+				path = ""
+			}
+		}
+	}
+	if path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		if err = json.NewDecoder(file).Decode(s); err != nil {
+			return nil, err
+		}
+	}
+
+	fromEnv, err := autoscaler.ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	cfConfig := fromEnv.CFConfig
+	envStrings := map[*string]string{
+		&s.API:              cfConfig.CCApiURL,
+		&s.Username:         cfConfig.Username,
+		&s.Password:         cfConfig.Password,
+		&s.ClientID:         cfConfig.ClientID,
+		&s.ClientSecret:     cfConfig.ClientSecret,
+		&s.CACertFile:       cfConfig.CACertFile,
+		&s.ClientCertFile:   cfConfig.ClientCertFile,
+		&s.ClientKeyFile:    cfConfig.ClientKeyFile,
+		&s.ProxyURL:         cfConfig.ProxyURL,
+		&s.NoProxy:          cfConfig.NoProxy,
+		&s.AutoscalerAPIUrl: fromEnv.AutoscalerAPIUrl,
+		&s.InstanceGUID:     fromEnv.InstanceGUID,
+		&s.Org:              os.Getenv("CF_ORG"),
+		&s.Space:            os.Getenv("CF_SPACE"),
+		&s.InstanceName:     os.Getenv("AUTOSCALER_INSTANCE_NAME"),
+	}
+	for value, env := range envStrings {
+		if env != "" {
+			*value = env
+		}
+	}
+	if os.Getenv("CF_SKIP_SSL_VALIDATION") != "" {
+		s.SkipSslValidation = cfConfig.SkipSslValidation
+	}
+
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "api":
+			s.API = fromFlags.API
+		case "username":
+			s.Username = fromFlags.Username
+		case "password":
+			s.Password = fromFlags.Password
+		case "client-id":
+			s.ClientID = fromFlags.ClientID
+		case "client-secret":
+			s.ClientSecret = fromFlags.ClientSecret
+		case "skip-ssl-validation":
+			s.SkipSslValidation = fromFlags.SkipSslValidation
+		case "ca-cert":
+			s.CACertFile = fromFlags.CACertFile
+		case "client-cert":
+			s.ClientCertFile = fromFlags.ClientCertFile
+		case "client-key":
+			s.ClientKeyFile = fromFlags.ClientKeyFile
+		case "proxy":
+			s.ProxyURL = fromFlags.ProxyURL
+		case "no-proxy":
+			s.NoProxy = fromFlags.NoProxy
+		case "autoscaler-api":
+			s.AutoscalerAPIUrl = fromFlags.AutoscalerAPIUrl
+		case "instance-guid":
+			s.InstanceGUID = fromFlags.InstanceGUID
+		case "org":
+			s.Org = fromFlags.Org
+		case "space":
+			s.Space = fromFlags.Space
+		case "instance-name":
+			s.InstanceName = fromFlags.InstanceName
+		}
+	})
+	return s, nil
+}
+
+// resolveConfig turns the settings into the configuration of an Autoscaler Client, reusing the login of the cf CLI
+// when no credentials are set, and resolving the GUID of the service instance through Cloud Controller when it is
+// given by name
+func (s *settings) resolveConfig() (*autoscaler.Config, error) {
+	config := s.config()
+	if s.Username == "" && s.ClientID == "" {
+		cfConfig, err := autoscaler.CFConfigFromCFHome()
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("Could not reuse the login of the cf CLI: %v", err)
+		}
+		if err == nil {
+			if s.API != "" {
+				cfConfig.CCApiURL = s.API
+			}
+			cfConfig.SkipSslValidation = cfConfig.SkipSslValidation || s.SkipSslValidation
+			cfConfig.CACertFile = s.CACertFile
+			cfConfig.ClientCertFile, cfConfig.ClientKeyFile = s.ClientCertFile, s.ClientKeyFile
+			cfConfig.ProxyURL, cfConfig.NoProxy = s.ProxyURL, s.NoProxy
+			config.CFConfig = cfConfig
+		}
+	}
+	if config.InstanceGUID != "" || s.InstanceName == "" {
+		return config, nil
+	}
+	return autoscaler.ConfigForServiceInstance(config, s.Org, s.Space, s.InstanceName)
+}
+
+// config turns the settings into the configuration of an Autoscaler Client
+func (s *settings) config() *autoscaler.Config {
+	return &autoscaler.Config{
+		CFConfig: &autoscaler.CFConfig{
+			CCApiURL:          s.API,
+			Username:          s.Username,
+			Password:          s.Password,
+			ClientID:          s.ClientID,
+			ClientSecret:      s.ClientSecret,
+			SkipSslValidation: s.SkipSslValidation,
+			CACertFile:        s.CACertFile,
+			ClientCertFile:    s.ClientCertFile,
+			ClientKeyFile:     s.ClientKeyFile,
+			ProxyURL:          s.ProxyURL,
+			NoProxy:           s.NoProxy,
+		},
+		AutoscalerAPIUrl: s.AutoscalerAPIUrl,
+		InstanceGUID:     s.InstanceGUID,
+		RetryPolicy:      autoscaler.DefaultRetryPolicy(),
+	}
+}
@@ -0,0 +1,98 @@
+// Command autoscalerctl manages App Autoscaler bindings, scheduled limit changes, rules and events from the command line.
+//
+// Usage:
+//
+//	autoscalerctl [global flags] <resource> <action> [flags] [arguments]
+//
+// The connection settings are read from a JSON config file (--config, $AUTOSCALERCTL_CONFIG or ~/.autoscalerctl.json),
+// then from the environment and then from the global flags, each overriding the previous one.
+// Without any credentials, the login of the cf CLI is reused.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/bijukunjummen/app-autoscaler-client"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs autoscalerctl with the given arguments, returning the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("autoscalerctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var fromFlags settings
+	fromFlags.bind(flags)
+	configPath := flags.String("config", "", "path to a JSON config file [$AUTOSCALERCTL_CONFIG]")
+	flags.Usage = func() { usage(stderr, flags) }
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() < 2 {
+		flags.Usage()
+		return 2
+	}
+
+	resource, action := flags.Arg(0), flags.Arg(1)
+	cmd, ok := commands[resource][action]
+	if !ok {
+		fmt.Fprintf(stderr, "Unknown command: %s %s\n\n", resource, action)
+		flags.Usage()
+		return 2
+	}
+
+	s, err := loadSettings(*configPath, flags, &fromFlags)
+	if err != nil {
+		fmt.Fprintf(stderr, "Could not load settings: %v\n", err)
+		return 1
+	}
+
+	config, err := s.resolveConfig()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	client, err := autoscaler.NewClient(config)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	if err = cmd.run(client, flags.Args()[2:], stdout); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func usage(out io.Writer, flags *flag.FlagSet) {
+	fmt.Fprintf(out, "Usage: autoscalerctl [global flags] <resource> <action> [flags] [arguments]\n\nCommands:\n")
+
+	var names []string
+	for resource, actions := range commands {
+		for action := range actions {
+			names = append(names, resource+" "+action)
+		}
+	}
+	sort.Strings(names)
+
+	writer := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	for _, name := range names {
+		var resource, action string
+		fmt.Sscan(name, &resource, &action)
+		fmt.Fprintf(writer, "  %s\t%s\n", name, commands[resource][action].usage)
+	}
+	writer.Flush()
+
+	fmt.Fprintf(out, "\nGlobal flags:\n")
+	flags.PrintDefaults()
+}
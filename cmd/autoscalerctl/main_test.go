@@ -0,0 +1,361 @@
+package main
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/bijukunjummen/app-autoscaler-client"
+	"github.com/onsi/gomega/ghttp"
+)
+
+// environment lists the variables autoscalerctl reads
+var environment = []string{
+	"AUTOSCALERCTL_CONFIG", "HOME", "CF_HOME", "CF_API", "CF_USERNAME", "CF_PASSWORD", "CF_CLIENT_ID", "CF_CLIENT_SECRET",
+	"CF_SKIP_SSL_VALIDATION", "CF_CA_CERT_FILE", "CF_CLIENT_CERT_FILE", "CF_CLIENT_KEY_FILE", "CF_PROXY_URL", "CF_NO_PROXY",
+	"AUTOSCALER_API_URL", "AUTOSCALER_INSTANCE_GUID", "AUTOSCALER_SERVICE_NAME", "VCAP_APPLICATION", "VCAP_SERVICES",
+	"CF_ORG", "CF_SPACE", "AUTOSCALER_INSTANCE_NAME",
+}
+
+// setenv replaces the variables autoscalerctl reads by the ones of env, returning a function restoring them
+func setenv(env map[string]string) func() {
+	saved := map[string]string{}
+	for _, name := range environment {
+		if value, ok := os.LookupEnv(name); ok {
+			saved[name] = value
+		}
+		os.Unsetenv(name)
+	}
+	for name, value := range env {
+		os.Setenv(name, value)
+	}
+	return func() {
+		for _, name := range environment {
+			os.Unsetenv(name)
+		}
+		for name, value := range saved {
+			os.Setenv(name, value)
+		}
+	}
+}
+
+var _ = Describe("autoscalerctl", func() {
+
+	Context("Given settings from a file, the environment and flags", func() {
+		var configPath string
+
+		BeforeEach(func() {
+			dir, err := ioutil.TempDir("", "autoscalerctl")
+			Ω(err).Should(BeNil())
+			configPath = filepath.Join(dir, "config.json")
+			Ω(ioutil.WriteFile(configPath, []byte(`{
+				"api": "https://api.file.example.com",
+				"username": "file-user",
+				"password": "file-password",
+				"autoscaler_api_url": "https://autoscale.file.example.com/api",
+				"instance_guid": "file-instance"
+			}`), 0600)).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(filepath.Dir(configPath))
+		})
+
+		It("Should let the environment override the file and the flags override the environment", func() {
+			env := map[string]string{
+				"CF_USERNAME":              "env-user",
+				"AUTOSCALER_INSTANCE_GUID": "env-instance",
+				"CF_SKIP_SSL_VALIDATION":   "true",
+				"CF_PROXY_URL":             "http://proxy.example.com:3128",
+			}
+			flags := flag.NewFlagSet("test", flag.ContinueOnError)
+			var fromFlags settings
+			fromFlags.bind(flags)
+			Ω(flags.Parse([]string{"--instance-guid", "flag-instance", "--no-proxy", "example.org"})).Should(Succeed())
+
+			restore := setenv(env)
+			s, err := loadSettings(configPath, flags, &fromFlags)
+			restore()
+
+			Ω(err).Should(BeNil())
+			Ω(s.API).Should(Equal("https://api.file.example.com"))
+			Ω(s.Password).Should(Equal("file-password"))
+			Ω(s.Username).Should(Equal("env-user"))
+			Ω(s.SkipSslValidation).Should(BeTrue())
+			Ω(s.InstanceGUID).Should(Equal("flag-instance"))
+			Ω(s.config().AutoscalerAPIUrl).Should(Equal("https://autoscale.file.example.com/api"))
+			Ω(s.config().CFConfig.ProxyURL).Should(Equal("http://proxy.example.com:3128"))
+			Ω(s.config().CFConfig.NoProxy).Should(Equal("example.org"))
+		})
+
+		It("Should read the environment like the library, VCAP variables included", func() {
+			env := map[string]string{
+				"CF_SKIP_SSL_VALIDATION": "false",
+				"VCAP_SERVICES": `{"app-autoscaler": [{"name": "autoscaler", "label": "app-autoscaler", "instance_guid": "vcap-instance",
+					"credentials": {"api_url": "https://autoscale.vcap.example.com/api"}}]}`,
+			}
+			Ω(ioutil.WriteFile(configPath, []byte(`{"skip_ssl_validation": true}`), 0600)).Should(Succeed())
+			flags := flag.NewFlagSet("test", flag.ContinueOnError)
+			var fromFlags settings
+			fromFlags.bind(flags)
+
+			restore := setenv(env)
+			s, err := loadSettings(configPath, flags, &fromFlags)
+			restore()
+
+			Ω(err).Should(BeNil())
+			Ω(s.SkipSslValidation).Should(BeFalse())
+			Ω(s.InstanceGUID).Should(Equal("vcap-instance"))
+			Ω(s.AutoscalerAPIUrl).Should(Equal("https://autoscale.vcap.example.com/api"))
+		})
+	})
+
+	Context("Given an Autoscaler API", func() {
+		var server *ghttp.Server
+		var stdout, stderr *bytes.Buffer
+		var env map[string]string
+
+		BeforeEach(func() {
+			server = ghttp.NewServer()
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v2/info"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, autoscaler.Endpoint{
+						AuthorizationEndpoint: server.URL(),
+						TokenEndpoint:         server.URL(),
+					}),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/oauth/token"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, autoscaler.AccessToken{
+						Token: "test-token",
+					}),
+				),
+			)
+			stdout, stderr = &bytes.Buffer{}, &bytes.Buffer{}
+			env = map[string]string{
+				"CF_API":                   server.URL(),
+				"CF_USERNAME":              "user",
+				"CF_PASSWORD":              "pwd",
+				"AUTOSCALER_API_URL":       server.URL() + "/api",
+				"AUTOSCALER_INSTANCE_GUID": "instanceid",
+			}
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		runWithEnv := func(args []string) int {
+			defer setenv(env)()
+			return run(args, stdout, stderr)
+		}
+
+		It("Should list the bindings", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/instances/instanceid/bindings"),
+					ghttp.RespondWith(http.StatusOK, `{"resources": [{"guid": "binding-1", "app_name": "app-1", "min_instances": 2, "max_instances": 5}]}`),
+				),
+			)
+
+			code := runWithEnv([]string{"bindings", "list"})
+
+			Ω(code).Should(Equal(0), stderr.String())
+			Ω(stdout.String()).Should(ContainSubstring("binding-1"))
+			Ω(stdout.String()).Should(ContainSubstring("app-1"))
+		})
+
+		It("Should resolve the service instance given by name", func() {
+			delete(env, "AUTOSCALER_INSTANCE_GUID")
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v2/organizations", "q=name%3Aacme"),
+					ghttp.RespondWith(http.StatusOK, `{"resources": [{"metadata": {"guid": "org-1"}}]}`),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v2/organizations/org-1/spaces", "q=name%3Aproduction"),
+					ghttp.RespondWith(http.StatusOK, `{"resources": [{"metadata": {"guid": "space-1"}}]}`),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v2/spaces/space-1/service_instances", "q=name%3Aautoscaler"),
+					ghttp.RespondWith(http.StatusOK, `{"resources": [{"metadata": {"guid": "instance-1"}}]}`),
+				),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, autoscaler.Endpoint{
+					AuthorizationEndpoint: server.URL(),
+					TokenEndpoint:         server.URL(),
+				}),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, autoscaler.AccessToken{
+					Token: "test-token",
+				}),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/instances/instance-1/bindings"),
+					ghttp.RespondWith(http.StatusOK, `{"resources": []}`),
+				),
+			)
+
+			code := runWithEnv([]string{"--org", "acme", "--space", "production", "--instance-name", "autoscaler", "bindings", "list"})
+
+			Ω(code).Should(Equal(0), stderr.String())
+		})
+
+		It("Should reuse the login of the cf CLI without credentials", func() {
+			home, err := ioutil.TempDir("", "cf-home")
+			Ω(err).Should(BeNil())
+			defer os.RemoveAll(home)
+			Ω(os.MkdirAll(filepath.Join(home, ".cf"), 0700)).Should(Succeed())
+			Ω(ioutil.WriteFile(filepath.Join(home, ".cf", "config.json"),
+				[]byte(`{"Target": "`+server.URL()+`", "AccessToken": "bearer cf-token"}`), 0600)).Should(Succeed())
+			delete(env, "CF_API")
+			delete(env, "CF_USERNAME")
+			delete(env, "CF_PASSWORD")
+			env["CF_HOME"] = home
+			server.SetHandler(1, ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/instances/instanceid/bindings"),
+				ghttp.VerifyHeaderKV("Authorization", "Bearer cf-token"),
+				ghttp.RespondWith(http.StatusOK, `{"resources": []}`),
+			))
+
+			code := runWithEnv([]string{"bindings", "list"})
+
+			Ω(code).Should(Equal(0), stderr.String())
+		})
+
+		It("Should fail on a cf CLI config it cannot reuse", func() {
+			home, err := ioutil.TempDir("", "cf-home")
+			Ω(err).Should(BeNil())
+			defer os.RemoveAll(home)
+			Ω(os.MkdirAll(filepath.Join(home, ".cf"), 0700)).Should(Succeed())
+			Ω(ioutil.WriteFile(filepath.Join(home, ".cf", "config.json"), []byte(`{"Target": "`+server.URL()+`"}`), 0600)).Should(Succeed())
+			delete(env, "CF_USERNAME")
+			delete(env, "CF_PASSWORD")
+			env["CF_HOME"] = home
+
+			code := runWithEnv([]string{"bindings", "list"})
+
+			Ω(code).Should(Equal(1))
+			Ω(stderr.String()).Should(ContainSubstring("Could not reuse the login of the cf CLI: Not logged in"))
+			Ω(server.ReceivedRequests()).Should(BeEmpty())
+		})
+
+		It("Should disable a binding", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/bindings/binding-1"),
+					ghttp.RespondWith(http.StatusOK, `{"guid": "binding-1", "min_instances": 2, "max_instances": 5, "enabled": true}`),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/api/bindings/binding-1"),
+					func(w http.ResponseWriter, req *http.Request) {
+						var sent map[string]interface{}
+						Ω(json.NewDecoder(req.Body).Decode(&sent)).Should(Succeed())
+						Ω(sent["enabled"]).Should(Equal(false))
+						Ω(sent["min_instances"]).Should(BeEquivalentTo(2))
+					},
+					ghttp.RespondWith(http.StatusOK, `{"guid": "binding-1", "enabled": false}`),
+				),
+			)
+
+			code := runWithEnv([]string{"bindings", "disable", "binding-1"})
+
+			Ω(code).Should(Equal(0), stderr.String())
+		})
+
+		It("Should create a scheduled limit change", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/api/bindings/binding-1/scheduled_limit_changes"),
+					ghttp.RespondWith(http.StatusCreated, `{"guid": "schedule-1", "min_instances": 4, "max_instances": 8}`),
+				),
+			)
+
+			code := runWithEnv([]string{"schedules", "create", "binding-1", "--executes-at", "2030-01-01T09:00:00Z", "--min", "4", "--max", "8"})
+
+			Ω(code).Should(Equal(0), stderr.String())
+			Ω(stdout.String()).Should(ContainSubstring("schedule-1"))
+		})
+
+		It("Should create scheduled limit changes from a cron expression", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/api/bindings/binding-1/scheduled_limit_changes"),
+					func(w http.ResponseWriter, r *http.Request) {
+						var change autoscaler.ScheduledLimitChange
+						Ω(json.NewDecoder(r.Body).Decode(&change)).Should(Succeed())
+						Ω(change.Recurrence).Should(Equal(autoscaler.Weekdays))
+						Ω(change.ExecutesAt.Format("15:04")).Should(Equal("09:00"))
+					},
+					ghttp.RespondWith(http.StatusCreated, `{"guid": "schedule-1"}`),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/api/bindings/binding-1/scheduled_limit_changes"),
+					ghttp.RespondWith(http.StatusCreated, `{"guid": "schedule-2"}`),
+				),
+			)
+
+			code := runWithEnv([]string{"schedules", "create", "binding-1", "--cron", "0 9,18 * * mon-fri", "--min", "4", "--max", "8"})
+
+			Ω(code).Should(Equal(0), stderr.String())
+			Ω(stdout.String()).Should(ContainSubstring("schedule-2"))
+		})
+
+		It("Should plan a policy without applying it", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/bindings/binding-1"),
+					ghttp.RespondWith(http.StatusOK, `{"guid": "binding-1", "min_instances": 2, "max_instances": 5, "enabled": true}`),
+				),
+			)
+			dir, err := ioutil.TempDir("", "autoscalerctl")
+			Ω(err).Should(BeNil())
+			defer os.RemoveAll(dir)
+			policyPath := filepath.Join(dir, "policy.yml")
+			Ω(ioutil.WriteFile(policyPath, []byte("min_instances: 3\nmax_instances: 5\n"), 0600)).Should(Succeed())
+
+			code := runWithEnv([]string{"policy", "plan", "binding-1", "--file", policyPath})
+
+			Ω(code).Should(Equal(0), stderr.String())
+			Ω(stdout.String()).Should(ContainSubstring("min_instances 2 -> 3"))
+			Ω(len(server.ReceivedRequests())).Should(Equal(3))
+		})
+
+		It("Should find the binding of an application", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v2/apps/app-1/service_bindings"),
+					ghttp.RespondWith(http.StatusOK, `{"resources": [{"metadata": {"guid": "binding-1"}}]}`),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/bindings/binding-1"),
+					ghttp.RespondWith(http.StatusOK, `{"guid": "binding-1", "app_name": "checkout"}`),
+				),
+			)
+
+			code := runWithEnv([]string{"bindings", "find", "--app-guid", "app-1"})
+
+			Ω(code).Should(Equal(0), stderr.String())
+			Ω(stdout.String()).Should(ContainSubstring(`"app_name": "checkout"`))
+		})
+
+		It("Should fail on missing arguments", func() {
+			code := runWithEnv([]string{"rules", "delete", "binding-1"})
+
+			Ω(code).Should(Equal(1))
+			Ω(stderr.String()).Should(ContainSubstring("BINDING_GUID RULE_GUID"))
+		})
+
+		It("Should fail on unknown commands", func() {
+			code := runWithEnv([]string{"apps", "list"})
+
+			Ω(code).Should(Equal(2))
+			Ω(stderr.String()).Should(ContainSubstring("Unknown command"))
+		})
+	})
+})
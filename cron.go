@@ -0,0 +1,179 @@
+package autoscaler
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxCronScheduledLimitChanges is the most scheduled limit changes a cron expression may be converted into
+const MaxCronScheduledLimitChanges = 24
+
+// ScheduledLimitChangesFromCron converts a cron expression, made of the five fields minute, hour, day of month,
+// month and day of week, into the scheduled limit changes executing at the same times in the location.
+// The Autoscaler only knows about a time and the days of the week a change recurs on, so the day of month and
+// month fields must be "*", the "L", "W" and "#" special characters are not supported, and one change is returned
+// per time of day the expression matches, up to MaxCronScheduledLimitChanges.
+// Each change first executes at the first matching time from the given point in time and recurs in UTC,
+// with its days of the week shifted when the time of day falls on another day in UTC. The UTC offset of the
+// location at that first execution is kept, the changes do not follow later daylight saving time transitions.
+func ScheduledLimitChangesFromCron(expression string, location *time.Location, minInstances, maxInstances int, from time.Time) ([]ScheduledLimitChange, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("Invalid cron expression %q, expected the 5 fields minute, hour, day of month, month and day of week", expression)
+	}
+	for _, field := range fields {
+		if hasCronSpecialCharacter(field) {
+			return nil, fmt.Errorf("Unsupported cron expression %q, scheduled limit changes cannot represent L, W or #", expression)
+		}
+	}
+	if fields[2] != "*" && fields[2] != "?" {
+		return nil, fmt.Errorf("Unsupported cron expression %q, scheduled limit changes cannot recur on days of the month", expression)
+	}
+	if fields[3] != "*" {
+		return nil, fmt.Errorf("Unsupported cron expression %q, scheduled limit changes cannot recur on months", expression)
+	}
+
+	minutes, err := parseCronField(fields[0], 0, 59, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid minute in cron expression %q: %v", expression, err)
+	}
+	hours, err := parseCronField(fields[1], 0, 23, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid hour in cron expression %q: %v", expression, err)
+	}
+	if count := len(hours) * len(minutes); count > MaxCronScheduledLimitChanges {
+		return nil, fmt.Errorf("Unsupported cron expression %q, it executes at %d times of day, more than the %d scheduled limit changes allowed",
+			expression, count, MaxCronScheduledLimitChanges)
+	}
+	days := EveryDay
+	if fields[4] != "*" && fields[4] != "?" {
+		values, err := parseCronField(fields[4], 0, 7, dayNames)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid day of week in cron expression %q: %v", expression, err)
+		}
+		days = 0
+		for _, value := range values {
+			days = days.Set(time.Weekday(value % 7))
+		}
+	}
+
+	if location == nil {
+		location = time.UTC
+	}
+	from = from.In(location)
+
+	var changes []ScheduledLimitChange
+	for _, hour := range hours {
+		for _, minute := range minutes {
+			executesAt := nextCronTime(from, hour, minute, days)
+			utc := executesAt.UTC()
+			changes = append(changes, ScheduledLimitChange{
+				ExecutesAt:   &utc,
+				MinInstances: minInstances,
+				MaxInstances: maxInstances,
+				Recurrence:   shiftRecurrence(days, utc.Weekday()-executesAt.Weekday()),
+				Enabled:      true,
+			})
+		}
+	}
+	return changes, nil
+}
+
+// nextCronTime returns the first time at the hour and minute on one of the days from a point in time, in its location
+func nextCronTime(from time.Time, hour, minute int, days Recurrence) time.Time {
+	for day := 0; ; day++ {
+		at := time.Date(from.Year(), from.Month(), from.Day()+day, hour, minute, 0, 0, from.Location())
+		if !at.Before(from) && days.Has(at.Weekday()) {
+			return at
+		}
+	}
+}
+
+// shiftRecurrence moves every day of the Recurrence by a number of days, wrapping around the week
+func shiftRecurrence(recurrence Recurrence, shift time.Weekday) Recurrence {
+	var shifted Recurrence
+	for _, day := range recurrence.Days() {
+		shifted = shifted.Set((day + shift%7 + 7) % 7)
+	}
+	return shifted
+}
+
+// parseCronField parses a comma separated list of values, ranges like "1-5" and steps like "*/15" or "8-18/2"
+// between min and max, where values may also be given by their index in names
+func parseCronField(field string, min, max int, names []string) ([]int, error) {
+	seen := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if slash := strings.Index(part, "/"); slash >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[slash+1:]); err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid step %q", part[slash+1:])
+			}
+			part = part[:slash]
+		}
+
+		first, last := min, max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if first, err = parseCronValue(bounds[0], min, max, names); err != nil {
+				return nil, err
+			}
+			last = first
+			if len(bounds) == 2 {
+				if last, err = parseCronValue(bounds[1], min, max, names); err != nil {
+					return nil, err
+				}
+			} else if step > 1 {
+				last = max
+			}
+			if last < first {
+				return nil, fmt.Errorf("invalid range %q", part)
+			}
+		}
+
+		for value := first; value <= last; value += step {
+			seen[value] = true
+		}
+	}
+
+	var values []int
+	for value := range seen {
+		values = append(values, value)
+	}
+	sort.Ints(values)
+	return values, nil
+}
+
+// hasCronSpecialCharacter returns true if a field uses the last day "L", nearest weekday "W" or nth day "#" characters
+func hasCronSpecialCharacter(field string) bool {
+	if strings.Contains(field, "#") {
+		return true
+	}
+	for _, value := range strings.FieldsFunc(strings.ToUpper(field), func(r rune) bool { return r == ',' || r == '-' || r == '/' }) {
+		prefix := strings.TrimRight(value, "LW")
+		if prefix == value {
+			continue
+		}
+		if _, err := strconv.Atoi(prefix); err == nil || prefix == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func parseCronValue(value string, min, max int, names []string) (int, error) {
+	for index, name := range names {
+		if strings.EqualFold(value, name) {
+			return index, nil
+		}
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < min || number > max {
+		return 0, fmt.Errorf("invalid value %q, expected a number between %d and %d", value, min, max)
+	}
+	return number, nil
+}
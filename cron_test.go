@@ -0,0 +1,91 @@
+package autoscaler_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"time"
+
+	. "github.com/bijukunjummen/app-autoscaler-client"
+)
+
+var _ = Describe("ScheduledLimitChangesFromCron", func() {
+	// 2017-01-02 is a Monday
+	from := time.Date(2017, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	It("Should convert a cron expression to a recurring change", func() {
+		changes, err := ScheduledLimitChangesFromCron("30 9 * * mon-fri", time.UTC, 4, 10, from)
+
+		Ω(err).Should(BeNil())
+		Ω(len(changes)).Should(Equal(1))
+		Ω(*changes[0].ExecutesAt).Should(Equal(time.Date(2017, 1, 3, 9, 30, 0, 0, time.UTC)))
+		Ω(changes[0].Recurrence).Should(Equal(Weekdays))
+		Ω(changes[0].MinInstances).Should(Equal(4))
+		Ω(changes[0].MaxInstances).Should(Equal(10))
+		Ω(changes[0].Enabled).Should(BeTrue())
+	})
+
+	It("Should return one change per time of day", func() {
+		changes, err := ScheduledLimitChangesFromCron("0,30 8-18/5 * * *", time.UTC, 1, 2, from)
+
+		Ω(err).Should(BeNil())
+		var times []string
+		for _, change := range changes {
+			Ω(change.Recurrence).Should(Equal(EveryDay))
+			times = append(times, change.ExecutesAt.Format("2006-01-02 15:04"))
+		}
+		Ω(times).Should(Equal([]string{
+			"2017-01-03 08:00", "2017-01-03 08:30",
+			"2017-01-02 13:00", "2017-01-02 13:30",
+			"2017-01-02 18:00", "2017-01-02 18:30",
+		}))
+	})
+
+	It("Should shift the days of the week to UTC", func() {
+		tokyo := time.FixedZone("JST", 9*60*60)
+		changes, err := ScheduledLimitChangesFromCron("0 7 * * 1,3,5", tokyo, 1, 2, from)
+
+		Ω(err).Should(BeNil())
+		Ω(*changes[0].ExecutesAt).Should(Equal(time.Date(2017, 1, 3, 22, 0, 0, 0, time.UTC)))
+		Ω(changes[0].Recurrence).Should(Equal(RecurrenceOf(time.Sunday, time.Tuesday, time.Thursday)))
+
+		newYork := time.FixedZone("EST", -5*60*60)
+		changes, err = ScheduledLimitChangesFromCron("0 22 * * sat,0", newYork, 1, 2, from)
+
+		Ω(err).Should(BeNil())
+		Ω(*changes[0].ExecutesAt).Should(Equal(time.Date(2017, 1, 8, 3, 0, 0, 0, time.UTC)))
+		Ω(changes[0].Recurrence).Should(Equal(RecurrenceOf(time.Sunday, time.Monday)))
+	})
+
+	It("Should reject the cron features scheduled limit changes cannot represent", func() {
+		_, err := ScheduledLimitChangesFromCron("0 9 1 * *", time.UTC, 1, 2, from)
+		Ω(err).ShouldNot(BeNil())
+		Ω(err.Error()).Should(ContainSubstring("days of the month"))
+
+		_, err = ScheduledLimitChangesFromCron("0 9 * 1-6 *", time.UTC, 1, 2, from)
+		Ω(err.Error()).Should(ContainSubstring("months"))
+
+		_, err = ScheduledLimitChangesFromCron("0 9 * *", time.UTC, 1, 2, from)
+		Ω(err.Error()).Should(ContainSubstring("5 fields"))
+
+		_, err = ScheduledLimitChangesFromCron("0 24 * * *", time.UTC, 1, 2, from)
+		Ω(err.Error()).Should(ContainSubstring("Invalid hour"))
+
+		_, err = ScheduledLimitChangesFromCron("0 9 * * fri-mon", time.UTC, 1, 2, from)
+		Ω(err.Error()).Should(ContainSubstring("Invalid day of week"))
+
+		for _, expression := range []string{"0 9 * * 1#2", "0 9 * * 5L", "0 9 L * *", "0 9 15W * *"} {
+			_, err = ScheduledLimitChangesFromCron(expression, time.UTC, 1, 2, from)
+			Ω(err).Should(MatchError(ContainSubstring("Unsupported cron expression")), expression)
+		}
+	})
+
+	It("Should reject cron expressions executing at too many times of day", func() {
+		changes, err := ScheduledLimitChangesFromCron("0 * * * wed", time.UTC, 1, 2, from)
+		Ω(err).Should(BeNil())
+		Ω(len(changes)).Should(Equal(MaxCronScheduledLimitChanges))
+
+		_, err = ScheduledLimitChangesFromCron("*/5 * * * *", time.UTC, 1, 2, from)
+		Ω(err).Should(MatchError(ContainSubstring("288 times of day")))
+	})
+})
@@ -0,0 +1,42 @@
+package autoscaler
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/context"
+)
+
+// discoverAutoscalerAPIUrl derives the url of the Autoscaler API from the dashboard url of the service instance,
+// which the Autoscaler serves too. Without a dashboard url, the Autoscaler is expected on the autoscale host of the
+// domain Cloud Controller is served on, like https://autoscale.sys.example.com/api for https://api.sys.example.com
+func (client *DefaultClient) discoverAutoscalerAPIUrl(ctx context.Context, instanceGUID string) (string, error) {
+	if instanceGUID != "" {
+		instance, err := client.getCCResource(ctx, "/v2/service_instances/"+instanceGUID)
+		if err != nil {
+			return "", fmt.Errorf("Could not discover the Autoscaler API url: %v", err)
+		}
+		if apiURL := apiURLFromDashboard(instance.Entity.DashboardURL); apiURL != "" {
+			return apiURL, nil
+		}
+	}
+
+	ccURL, err := url.Parse(client.config.CFConfig.CCApiURL)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(ccURL.Host, "api.") {
+		return "", fmt.Errorf("Could not discover the Autoscaler API url from %s, set Config.AutoscalerAPIUrl", client.config.CFConfig.CCApiURL)
+	}
+	return fmt.Sprintf("%s://autoscale.%s/api", ccURL.Scheme, strings.TrimPrefix(ccURL.Host, "api.")), nil
+}
+
+// apiURLFromDashboard returns the url of the Autoscaler API served on the host of a dashboard url, empty if none
+func apiURLFromDashboard(dashboardURL string) string {
+	dashboard, err := url.Parse(dashboardURL)
+	if err != nil || dashboard.Host == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s://%s/api", dashboard.Scheme, dashboard.Host)
+}
@@ -0,0 +1,53 @@
+package autoscaler_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/bijukunjummen/app-autoscaler-client"
+	"github.com/bijukunjummen/app-autoscaler-client/autoscalertest"
+)
+
+var _ = Describe("Autoscaler API discovery", func() {
+	var server *autoscalertest.Server
+	var config *Config
+
+	BeforeEach(func() {
+		server = autoscalertest.NewServer()
+		server.AddBinding(Binding{AppName: "checkout"})
+		config = server.Config()
+		config.AutoscalerAPIUrl = ""
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Should discover the API from the dashboard url of the service instance", func() {
+		server.AddServiceInstance("acme", "production", "autoscaler")
+
+		client, err := NewClient(config)
+		Ω(err).Should(BeNil())
+		serviceInstances, err := client.GetServiceBindings()
+
+		Ω(err).Should(BeNil())
+		Ω(serviceInstances.BindingResources[0].AppName).Should(Equal("checkout"))
+		Ω(config.AutoscalerAPIUrl).Should(BeEmpty())
+	})
+
+	It("Should fill in the API of a service instance resolved by name", func() {
+		server.AddServiceInstance("acme", "production", "autoscaler")
+		config.InstanceGUID = ""
+
+		resolved, err := ConfigForServiceInstance(config, "acme", "production", "autoscaler")
+
+		Ω(err).Should(BeNil())
+		Ω(resolved.AutoscalerAPIUrl).Should(Equal(server.URL + "/api"))
+	})
+
+	It("Should fail when the API cannot be discovered", func() {
+		_, err := NewClient(config)
+
+		Ω(err).Should(MatchError(ContainSubstring("Could not discover the Autoscaler API url")))
+	})
+})
@@ -0,0 +1,135 @@
+package autoscaler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// vcapService is a service bound to an application, as listed in VCAP_SERVICES
+type vcapService struct {
+	Name         string                 `json:"name"`
+	InstanceName string                 `json:"instance_name"`
+	InstanceGUID string                 `json:"instance_guid"`
+	Label        string                 `json:"label"`
+	Tags         []string               `json:"tags"`
+	Credentials  map[string]interface{} `json:"credentials"`
+}
+
+// vcapApplication is the part of VCAP_APPLICATION used to configure the client
+type vcapApplication struct {
+	CFAPI string `json:"cf_api"`
+}
+
+// ConfigFromEnv builds a Config out of the environment, as set for a Cloud Foundry application bound to the
+// autoscaler service. The Cloud Controller url is read from CF_API or from VCAP_APPLICATION, and the credentials from
+// CF_USERNAME and CF_PASSWORD, or CF_CLIENT_ID and CF_CLIENT_SECRET. CF_SKIP_SSL_VALIDATION skips verifying certificates,
+// CF_CA_CERT_FILE, CF_CLIENT_CERT_FILE and CF_CLIENT_KEY_FILE are the PEM files of the CA certificates to trust and of
+// the client certificate, and CF_PROXY_URL and CF_NO_PROXY the proxy settings.
+// The Autoscaler API url and the service instance are read from AUTOSCALER_API_URL and AUTOSCALER_INSTANCE_GUID,
+// or from the autoscaler service in VCAP_SERVICES, chosen by AUTOSCALER_SERVICE_NAME when several are bound.
+func ConfigFromEnv() (*Config, error) {
+	return configFromEnv(os.Getenv)
+}
+
+func configFromEnv(getenv func(string) string) (*Config, error) {
+	config := &Config{
+		CFConfig: &CFConfig{
+			CCApiURL:       getenv("CF_API"),
+			Username:       getenv("CF_USERNAME"),
+			Password:       getenv("CF_PASSWORD"),
+			ClientID:       getenv("CF_CLIENT_ID"),
+			ClientSecret:   getenv("CF_CLIENT_SECRET"),
+			CACertFile:     getenv("CF_CA_CERT_FILE"),
+			ClientCertFile: getenv("CF_CLIENT_CERT_FILE"),
+			ClientKeyFile:  getenv("CF_CLIENT_KEY_FILE"),
+			ProxyURL:       getenv("CF_PROXY_URL"),
+			NoProxy:        getenv("CF_NO_PROXY"),
+		},
+		AutoscalerAPIUrl: getenv("AUTOSCALER_API_URL"),
+		InstanceGUID:     getenv("AUTOSCALER_INSTANCE_GUID"),
+	}
+	if skip := getenv("CF_SKIP_SSL_VALIDATION"); skip != "" {
+		skipSslValidation, err := strconv.ParseBool(skip)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid CF_SKIP_SSL_VALIDATION %q: %v", skip, err)
+		}
+		config.CFConfig.SkipSslValidation = skipSslValidation
+	}
+
+	if application := getenv("VCAP_APPLICATION"); application != "" && config.CFConfig.CCApiURL == "" {
+		var vcap vcapApplication
+		if err := json.Unmarshal([]byte(application), &vcap); err != nil {
+			return nil, fmt.Errorf("Invalid VCAP_APPLICATION: %v", err)
+		}
+		config.CFConfig.CCApiURL = vcap.CFAPI
+	}
+
+	if services := getenv("VCAP_SERVICES"); services != "" && (config.AutoscalerAPIUrl == "" || config.InstanceGUID == "") {
+		service, err := findAutoscalerService(services, getenv("AUTOSCALER_SERVICE_NAME"))
+		if err != nil {
+			return nil, err
+		}
+		if service != nil {
+			if config.InstanceGUID == "" {
+				config.InstanceGUID = service.InstanceGUID
+			}
+			if config.AutoscalerAPIUrl == "" {
+				config.AutoscalerAPIUrl = service.apiURL()
+			}
+		}
+	}
+	return config, nil
+}
+
+// findAutoscalerService finds the bound autoscaler service in VCAP_SERVICES, by name if given,
+// nil if there is none
+func findAutoscalerService(services, name string) (*vcapService, error) {
+	var vcap map[string][]vcapService
+	if err := json.Unmarshal([]byte(services), &vcap); err != nil {
+		return nil, fmt.Errorf("Invalid VCAP_SERVICES: %v", err)
+	}
+
+	var found []vcapService
+	for label, bound := range vcap {
+		for _, service := range bound {
+			if name != "" {
+				if service.Name == name || service.InstanceName == name {
+					found = append(found, service)
+				}
+			} else if label == AutoscalerServiceLabel || service.Label == AutoscalerServiceLabel || service.hasTag("autoscaler") {
+				found = append(found, service)
+			}
+		}
+	}
+
+	switch {
+	case len(found) == 0 && name != "":
+		return nil, &NotFoundError{Resource: "service in VCAP_SERVICES", Name: name}
+	case len(found) == 0:
+		return nil, nil
+	case len(found) > 1:
+		return nil, fmt.Errorf("Found %d autoscaler services in VCAP_SERVICES, set AUTOSCALER_SERVICE_NAME to choose one", len(found))
+	}
+	return &found[0], nil
+}
+
+func (service *vcapService) hasTag(tag string) bool {
+	for _, serviceTag := range service.Tags {
+		if serviceTag == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// apiURL returns the Autoscaler API url from the credentials of the service, empty if not found
+func (service *vcapService) apiURL() string {
+	for _, key := range []string{"api_url", "url", "uri"} {
+		if value, ok := service.Credentials[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
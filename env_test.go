@@ -0,0 +1,144 @@
+package autoscaler_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"os"
+
+	. "github.com/bijukunjummen/app-autoscaler-client"
+)
+
+var _ = Describe("Config from the environment", func() {
+	variables := []string{
+		"CF_API", "CF_USERNAME", "CF_PASSWORD", "CF_CLIENT_ID", "CF_CLIENT_SECRET", "CF_SKIP_SSL_VALIDATION",
+		"CF_CA_CERT_FILE", "CF_CLIENT_CERT_FILE", "CF_CLIENT_KEY_FILE", "CF_PROXY_URL", "CF_NO_PROXY",
+		"AUTOSCALER_API_URL", "AUTOSCALER_INSTANCE_GUID", "AUTOSCALER_SERVICE_NAME", "VCAP_APPLICATION", "VCAP_SERVICES",
+	}
+	setenv := func(env map[string]string) {
+		for name, value := range env {
+			os.Setenv(name, value)
+		}
+	}
+
+	vcapServices := `{
+		"app-autoscaler": [{
+			"name": "autoscaler",
+			"instance_name": "autoscaler",
+			"instance_guid": "instance-1",
+			"label": "app-autoscaler",
+			"tags": ["autoscaler"],
+			"credentials": {"api_url": "https://autoscale.example.com/api"}
+		}],
+		"p-mysql": [{"name": "db", "label": "p-mysql", "credentials": {"uri": "mysql://db"}}]
+	}`
+
+	AfterEach(func() {
+		for _, name := range variables {
+			os.Unsetenv(name)
+		}
+	})
+
+	It("Should read the standard variables", func() {
+		setenv(map[string]string{
+			"CF_API":                   "https://api.example.com",
+			"CF_CLIENT_ID":             "client",
+			"CF_CLIENT_SECRET":         "secret",
+			"CF_SKIP_SSL_VALIDATION":   "true",
+			"AUTOSCALER_API_URL":       "https://autoscale.example.com/api",
+			"AUTOSCALER_INSTANCE_GUID": "instance-1",
+		})
+
+		config, err := ConfigFromEnv()
+
+		Ω(err).Should(BeNil())
+		Ω(config.CFConfig.CCApiURL).Should(Equal("https://api.example.com"))
+		Ω(config.CFConfig.ClientID).Should(Equal("client"))
+		Ω(config.CFConfig.ClientSecret).Should(Equal("secret"))
+		Ω(config.CFConfig.SkipSslValidation).Should(BeTrue())
+		Ω(config.AutoscalerAPIUrl).Should(Equal("https://autoscale.example.com/api"))
+		Ω(config.InstanceGUID).Should(Equal("instance-1"))
+	})
+
+	It("Should read the TLS variables", func() {
+		setenv(map[string]string{
+			"CF_CA_CERT_FILE":     "/etc/ca.pem",
+			"CF_CLIENT_CERT_FILE": "/etc/client.pem",
+			"CF_CLIENT_KEY_FILE":  "/etc/client.key",
+		})
+
+		config, err := ConfigFromEnv()
+
+		Ω(err).Should(BeNil())
+		Ω(config.CFConfig.CACertFile).Should(Equal("/etc/ca.pem"))
+		Ω(config.CFConfig.ClientCertFile).Should(Equal("/etc/client.pem"))
+		Ω(config.CFConfig.ClientKeyFile).Should(Equal("/etc/client.key"))
+	})
+
+	It("Should read the proxy variables", func() {
+		setenv(map[string]string{
+			"CF_PROXY_URL": "http://proxy.example.com:3128",
+			"CF_NO_PROXY":  ".internal",
+		})
+
+		config, err := ConfigFromEnv()
+
+		Ω(err).Should(BeNil())
+		Ω(config.CFConfig.ProxyURL).Should(Equal("http://proxy.example.com:3128"))
+		Ω(config.CFConfig.NoProxy).Should(Equal(".internal"))
+	})
+
+	It("Should discover the API and service instance from VCAP variables", func() {
+		setenv(map[string]string{
+			"CF_USERNAME":      "user",
+			"CF_PASSWORD":      "pwd",
+			"VCAP_APPLICATION": `{"application_name": "tooling", "cf_api": "https://api.vcap.example.com"}`,
+			"VCAP_SERVICES":    vcapServices,
+		})
+
+		config, err := ConfigFromEnv()
+
+		Ω(err).Should(BeNil())
+		Ω(config.CFConfig.CCApiURL).Should(Equal("https://api.vcap.example.com"))
+		Ω(config.CFConfig.Username).Should(Equal("user"))
+		Ω(config.AutoscalerAPIUrl).Should(Equal("https://autoscale.example.com/api"))
+		Ω(config.InstanceGUID).Should(Equal("instance-1"))
+	})
+
+	It("Should let the standard variables override VCAP variables", func() {
+		setenv(map[string]string{
+			"CF_API":                   "https://api.example.com",
+			"AUTOSCALER_INSTANCE_GUID": "instance-2",
+			"VCAP_APPLICATION":         `{"cf_api": "https://api.vcap.example.com"}`,
+			"VCAP_SERVICES":            vcapServices,
+		})
+
+		config, err := ConfigFromEnv()
+
+		Ω(err).Should(BeNil())
+		Ω(config.CFConfig.CCApiURL).Should(Equal("https://api.example.com"))
+		Ω(config.InstanceGUID).Should(Equal("instance-2"))
+		Ω(config.AutoscalerAPIUrl).Should(Equal("https://autoscale.example.com/api"))
+	})
+
+	It("Should choose the autoscaler service by name", func() {
+		setenv(map[string]string{
+			"AUTOSCALER_SERVICE_NAME": "missing",
+			"VCAP_SERVICES":           vcapServices,
+		})
+
+		_, err := ConfigFromEnv()
+
+		Ω(IsNotFound(err)).Should(BeTrue())
+	})
+
+	It("Should fail on invalid variables", func() {
+		setenv(map[string]string{"CF_SKIP_SSL_VALIDATION": "maybe"})
+		_, err := ConfigFromEnv()
+		Ω(err).Should(MatchError(ContainSubstring("CF_SKIP_SSL_VALIDATION")))
+
+		setenv(map[string]string{"CF_SKIP_SSL_VALIDATION": "false", "VCAP_SERVICES": "{"})
+		_, err = ConfigFromEnv()
+		Ω(err).Should(MatchError(ContainSubstring("VCAP_SERVICES")))
+	})
+})
@@ -0,0 +1,118 @@
+package autoscaler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// APIError is returned when the Autoscaler API responds with an unexpected status code
+type APIError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	RequestID  string
+	Body       []byte
+	// Response holds the error document returned by the API, nil if the body could not be parsed
+	Response *ErrorResponse
+}
+
+// ErrorResponse - error document returned by the API, the fields set depend on the failure
+type ErrorResponse struct {
+	Error       string   `json:"error,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Message     string   `json:"message,omitempty"`
+	Errors      []string `json:"errors,omitempty"`
+}
+
+func (apiError *APIError) Error() string {
+	message := string(apiError.Body)
+	if apiError.Response != nil {
+		if detail := apiError.Response.detail(); detail != "" {
+			message = detail
+		}
+	}
+	return fmt.Sprintf("Bad Response: %s %s returned %d %s: %s",
+		apiError.Method, apiError.URL, apiError.StatusCode, http.StatusText(apiError.StatusCode), message)
+}
+
+func (errorResponse *ErrorResponse) detail() string {
+	switch {
+	case errorResponse.Description != "":
+		return errorResponse.Description
+	case errorResponse.Message != "":
+		return errorResponse.Message
+	case errorResponse.Error != "":
+		return errorResponse.Error
+	case len(errorResponse.Errors) > 0:
+		return fmt.Sprintf("%v", errorResponse.Errors)
+	}
+	return ""
+}
+
+// newAPIError builds an APIError out of an unexpected response, consuming its body
+func newAPIError(resp *http.Response) *APIError {
+	body, _ := ioutil.ReadAll(resp.Body)
+
+	apiError := &APIError{
+		StatusCode: resp.StatusCode,
+		Body:       body,
+		RequestID:  resp.Header.Get("X-Vcap-Request-Id"),
+	}
+	if apiError.RequestID == "" {
+		apiError.RequestID = resp.Header.Get("X-Request-Id")
+	}
+	if resp.Request != nil {
+		apiError.Method = resp.Request.Method
+		apiError.URL = resp.Request.URL.String()
+	}
+
+	var errorResponse ErrorResponse
+	if err := json.Unmarshal(body, &errorResponse); err == nil {
+		apiError.Response = &errorResponse
+	}
+	return apiError
+}
+
+// NotFoundError is returned when a resource looked up by name, like an application, does not exist
+type NotFoundError struct {
+	Resource string
+	Name     string
+}
+
+func (notFoundError *NotFoundError) Error() string {
+	return fmt.Sprintf("No %s %q found", notFoundError.Resource, notFoundError.Name)
+}
+
+// IsNotFound returns true if the error is an APIError with a 404 status code, or a NotFoundError
+func IsNotFound(err error) bool {
+	var notFoundError *NotFoundError
+	return hasStatusCode(err, http.StatusNotFound) || errors.As(err, &notFoundError)
+}
+
+// IsUnauthorized returns true if the error is an APIError with a 401 status code
+func IsUnauthorized(err error) bool {
+	return hasStatusCode(err, http.StatusUnauthorized)
+}
+
+// IsForbidden returns true if the error is an APIError with a 403 status code
+func IsForbidden(err error) bool {
+	return hasStatusCode(err, http.StatusForbidden)
+}
+
+// IsConflict returns true if the error is an APIError with a 409 status code
+func IsConflict(err error) bool {
+	return hasStatusCode(err, http.StatusConflict)
+}
+
+// IsUnprocessableEntity returns true if the error is an APIError with a 422 status code
+func IsUnprocessableEntity(err error) bool {
+	return hasStatusCode(err, http.StatusUnprocessableEntity)
+}
+
+func hasStatusCode(err error, statusCode int) bool {
+	var apiError *APIError
+	return errors.As(err, &apiError) && apiError.StatusCode == statusCode
+}
@@ -0,0 +1,98 @@
+package autoscaler_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"fmt"
+	"net/http"
+
+	. "github.com/bijukunjummen/app-autoscaler-client"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("API Errors", func() {
+	var server *ghttp.Server
+	var client Client
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v2/info"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, Endpoint{
+					AuthorizationEndpoint: server.URL(),
+					TokenEndpoint:         server.URL(),
+				}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/oauth/token"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, AccessToken{
+					Token: "test-token",
+				}),
+			),
+		)
+		var err error
+		client, err = NewClient(&Config{
+			CFConfig: &CFConfig{
+				CCApiURL:          server.URL(),
+				Username:          "user",
+				Password:          "pwd",
+				SkipSslValidation: true,
+			},
+			AutoscalerAPIUrl: server.URL() + "/api",
+			InstanceGUID:     "instanceid",
+		})
+		Ω(err).Should(BeNil())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Should carry the details of a failed call", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/bindings/missing"),
+				ghttp.RespondWith(http.StatusNotFound, `{"error": "not_found", "description": "Binding not found"}`,
+					http.Header{"X-Vcap-Request-Id": []string{"request-1"}}),
+			),
+		)
+
+		_, err := client.GetBinding("missing")
+
+		apiError, ok := err.(*APIError)
+		Ω(ok).Should(BeTrue())
+		Ω(apiError.StatusCode).Should(Equal(http.StatusNotFound))
+		Ω(apiError.Method).Should(Equal("GET"))
+		Ω(apiError.URL).Should(Equal(server.URL() + "/api/bindings/missing"))
+		Ω(apiError.RequestID).Should(Equal("request-1"))
+		Ω(apiError.Response.Error).Should(Equal("not_found"))
+		Ω(apiError.Error()).Should(ContainSubstring("Binding not found"))
+		Ω(IsNotFound(err)).Should(BeTrue())
+		Ω(IsConflict(err)).Should(BeFalse())
+	})
+
+	It("Should keep the raw body when it is not an error document", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/api/bindings/mybinding/scheduled_limit_changes/changeid"),
+				ghttp.RespondWith(http.StatusUnauthorized, "Unauthorized"),
+			),
+		)
+
+		err := client.DeleteScheduledLimitChange("mybinding", "changeid")
+
+		Ω(IsUnauthorized(err)).Should(BeTrue())
+		Ω(string(err.(*APIError).Body)).Should(Equal("Unauthorized"))
+		Ω(err.(*APIError).Response).Should(BeNil())
+	})
+
+	It("Should recognize wrapped API errors", func() {
+		err := fmt.Errorf("updating binding: %w", &APIError{StatusCode: http.StatusConflict})
+
+		Ω(IsConflict(err)).Should(BeTrue())
+		Ω(IsNotFound(err)).Should(BeFalse())
+		Ω(IsNotFound(nil)).Should(BeFalse())
+	})
+})
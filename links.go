@@ -0,0 +1,47 @@
+package autoscaler
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"golang.org/x/net/context"
+)
+
+// Follow ...
+func (client *DefaultClient) Follow(binding *BindingResource, rel string, out interface{}) error {
+	return client.FollowContext(context.Background(), binding, rel, out)
+}
+
+// FollowContext ...
+func (client *DefaultClient) FollowContext(ctx context.Context, binding *BindingResource, rel string, out interface{}) error {
+	link, ok := binding.Links[rel]
+	if !ok || link.Href == "" {
+		return fmt.Errorf("Binding %s has no %s link", binding.GUID, rel)
+	}
+	linkURL, err := resolveURL(client.config.AutoscalerAPIUrl, link.Href)
+	if err != nil {
+		return err
+	}
+
+	if value := reflect.ValueOf(out); value.Kind() == reflect.Ptr && value.Elem().Kind() == reflect.Slice {
+		return client.ListAll(ctx, linkURL, client.listOptions(), out)
+	}
+
+	request, err := client.httpClient.NewRequestWithContext(ctx, "GET", linkURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return newAPIError(resp)
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	return decoder.Decode(out)
+}
@@ -0,0 +1,110 @@
+package autoscaler_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"net/http"
+
+	. "github.com/bijukunjummen/app-autoscaler-client"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Links", func() {
+	var server *ghttp.Server
+	var client Client
+	var binding *BindingResource
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v2/info"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, Endpoint{
+					AuthorizationEndpoint: server.URL(),
+					TokenEndpoint:         server.URL(),
+				}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/oauth/token"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, AccessToken{
+					Token: "test-token",
+				}),
+			),
+		)
+		var err error
+		client, err = NewClient(&Config{
+			CFConfig: &CFConfig{
+				CCApiURL:          server.URL(),
+				Username:          "user",
+				Password:          "pwd",
+				SkipSslValidation: true,
+			},
+			AutoscalerAPIUrl: server.URL() + "/api",
+			InstanceGUID:     "instanceid",
+		})
+		Ω(err).Should(BeNil())
+
+		binding = &BindingResource{
+			Binding: Binding{GUID: "mybinding"},
+			Links: map[string]Link{
+				"self":                    {Href: "/api/bindings/mybinding"},
+				"events":                  {Href: "/api/bindings/mybinding/scaling_events"},
+				"scheduled_limit_changes": {Href: "/api/bindings/mybinding/scheduled_limit_changes"},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Should follow a link to a single resource", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/bindings/mybinding"),
+				ghttp.VerifyHeader(http.Header{
+					"Authorization": []string{"Bearer test-token"},
+				}),
+				ghttp.RespondWith(http.StatusOK, `{"guid": "mybinding", "app_name": "myapp"}`),
+			),
+		)
+
+		var self BindingResource
+		err := client.Follow(binding, "self", &self)
+
+		Ω(err).Should(BeNil())
+		Ω(self.AppName).Should(Equal("myapp"))
+	})
+
+	It("Should follow a link to a list of resources", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/bindings/mybinding/scaling_events"),
+				ghttp.RespondWith(http.StatusOK, `{
+					"resources": [{"guid": "event-1"}],
+					"pagination": {"next": {"href": "/api/bindings/mybinding/scaling_events?page=2"}}
+				}`),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/bindings/mybinding/scaling_events", "page=2"),
+				ghttp.RespondWith(http.StatusOK, `{"resources": [{"guid": "event-2"}]}`),
+			),
+		)
+
+		var events []ScalingDecision
+		err := client.Follow(binding, "events", &events)
+
+		Ω(err).Should(BeNil())
+		Ω(len(events)).Should(Equal(2))
+		Ω(events[1].GUID).Should(Equal("event-2"))
+	})
+
+	It("Should fail to follow a missing link", func() {
+		var rules []Rule
+		err := client.Follow(binding, "rules", &rules)
+
+		Ω(err).ShouldNot(BeNil())
+		Ω(len(server.ReceivedRequests())).Should(Equal(2))
+	})
+})
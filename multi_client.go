@@ -0,0 +1,215 @@
+package autoscaler
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+
+	"golang.org/x/net/context"
+)
+
+// AutoscalerServiceLabel is the label of the App Autoscaler service in the Cloud Foundry marketplace
+const AutoscalerServiceLabel = "app-autoscaler"
+
+// ServiceInstance is an autoscaler service instance, along with the names of the org and space it belongs to
+type ServiceInstance struct {
+	GUID  string `json:"guid"`
+	Name  string `json:"name"`
+	Org   string `json:"org"`
+	Space string `json:"space"`
+}
+
+// InstanceBinding is a Binding of one of the service instances spanned by a MultiClient
+type InstanceBinding struct {
+	BindingResource
+
+	ServiceInstance ServiceInstance `json:"service_instance"`
+}
+
+// InstanceError is returned when a call to one of the service instances spanned by a MultiClient fails
+type InstanceError struct {
+	InstanceGUID string
+	Err          error
+}
+
+func (instanceError *InstanceError) Error() string {
+	return fmt.Sprintf("Service instance %s: %v", instanceError.InstanceGUID, instanceError.Err)
+}
+
+// Unwrap returns the error the call to the service instance failed with
+func (instanceError *InstanceError) Unwrap() error {
+	return instanceError.Err
+}
+
+// MultiClient spans several autoscaler service instances, calling all of them with the same credentials
+type MultiClient struct {
+	// Concurrency is the maximum number of service instances called at once, all of them if not set
+	Concurrency int
+
+	httpClient OauthHTTPWrapper
+	config     *Config
+	instances  []ServiceInstance
+}
+
+// NewMultiClient ...
+func NewMultiClient(config *Config, instanceGUIDs ...string) (*MultiClient, error) {
+	return NewMultiClientWithContext(context.Background(), config, instanceGUIDs...)
+}
+
+// NewMultiClientWithContext creates a client spanning the given service instances, or every autoscaler service
+// instance visible to the user in Cloud Controller if none is given. The InstanceGUID of the config is ignored.
+// The names of the instances and of their orgs and spaces are resolved through Cloud Controller once, along with
+// the AutoscalerAPIUrl if not set.
+func NewMultiClientWithContext(ctx context.Context, config *Config, instanceGUIDs ...string) (*MultiClient, error) {
+	oauthWrapper, err := NewUAAClientWithContext(ctx, config.CFConfig)
+	if err != nil {
+		return nil, err
+	}
+	client := &DefaultClient{
+		httpClient: oauthWrapper,
+		config:     config,
+	}
+
+	var resources []ccResource
+	if len(instanceGUIDs) == 0 {
+		if resources, err = client.listAutoscalerInstances(ctx); err != nil {
+			return nil, err
+		}
+	}
+	for _, instanceGUID := range instanceGUIDs {
+		resource, err := client.getCCResource(ctx, "/v2/service_instances/"+instanceGUID)
+		if err != nil {
+			return nil, &InstanceError{InstanceGUID: instanceGUID, Err: err}
+		}
+		resources = append(resources, *resource)
+	}
+
+	names := make(map[string]*ccResource)
+	getNamed := func(path string) (*ccResource, error) {
+		if resource, ok := names[path]; ok {
+			return resource, nil
+		}
+		resource, err := client.getCCResource(ctx, path)
+		names[path] = resource
+		return resource, err
+	}
+
+	if config.AutoscalerAPIUrl == "" && len(resources) > 0 {
+		apiURL := apiURLFromDashboard(resources[0].Entity.DashboardURL)
+		if apiURL == "" {
+			if apiURL, err = client.discoverAutoscalerAPIUrl(ctx, ""); err != nil {
+				return nil, err
+			}
+		}
+		discovered := *config
+		discovered.AutoscalerAPIUrl = apiURL
+		config = &discovered
+	}
+
+	multiClient := &MultiClient{
+		httpClient: oauthWrapper,
+		config:     config,
+	}
+	for _, resource := range resources {
+		space, err := getNamed("/v2/spaces/" + resource.Entity.SpaceGUID)
+		if err != nil {
+			return nil, &InstanceError{InstanceGUID: resource.Metadata.GUID, Err: err}
+		}
+		org, err := getNamed("/v2/organizations/" + space.Entity.OrganizationGUID)
+		if err != nil {
+			return nil, &InstanceError{InstanceGUID: resource.Metadata.GUID, Err: err}
+		}
+		multiClient.instances = append(multiClient.instances, ServiceInstance{
+			GUID:  resource.Metadata.GUID,
+			Name:  resource.Entity.Name,
+			Org:   org.Entity.Name,
+			Space: space.Entity.Name,
+		})
+	}
+	return multiClient, nil
+}
+
+// listAutoscalerInstances lists the instances of every plan of the autoscaler service
+func (client *DefaultClient) listAutoscalerInstances(ctx context.Context) ([]ccResource, error) {
+	var services []ccResource
+	if err := client.listCCResources(ctx, "/v2/services", url.Values{"q": {"label:" + AutoscalerServiceLabel}}, &services); err != nil {
+		return nil, err
+	}
+
+	var instances []ccResource
+	for _, service := range services {
+		var plans []ccResource
+		if err := client.listCCResources(ctx, "/v2/services/"+service.Metadata.GUID+"/service_plans", nil, &plans); err != nil {
+			return nil, err
+		}
+		for _, plan := range plans {
+			if err := client.listCCResources(ctx, "/v2/service_plans/"+plan.Metadata.GUID+"/service_instances", nil, &instances); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return instances, nil
+}
+
+// Instances returns the service instances spanned by the client
+func (multiClient *MultiClient) Instances() []ServiceInstance {
+	return append([]ServiceInstance(nil), multiClient.instances...)
+}
+
+// Client returns a Client for a single service instance, sharing the credentials of the MultiClient
+func (multiClient *MultiClient) Client(instanceGUID string) Client {
+	config := *multiClient.config
+	config.InstanceGUID = instanceGUID
+	return &DefaultClient{
+		httpClient: multiClient.httpClient,
+		config:     &config,
+	}
+}
+
+// GetServiceBindings ...
+func (multiClient *MultiClient) GetServiceBindings() ([]InstanceBinding, error) {
+	return multiClient.GetServiceBindingsContext(context.Background())
+}
+
+// GetServiceBindingsContext gets the bindings of every service instance concurrently, in the order of the instances.
+// When some instances fail, the bindings of the others are returned along with the InstanceError of the first failure.
+func (multiClient *MultiClient) GetServiceBindingsContext(ctx context.Context) ([]InstanceBinding, error) {
+	results := make([][]InstanceBinding, len(multiClient.instances))
+	errs := make([]error, len(multiClient.instances))
+
+	concurrency := multiClient.Concurrency
+	if concurrency <= 0 {
+		concurrency = len(multiClient.instances)
+	}
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, instance := range multiClient.instances {
+		wg.Add(1)
+		go func(i int, instance ServiceInstance) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			serviceInstances, err := multiClient.Client(instance.GUID).GetServiceBindingsContext(ctx)
+			if err != nil {
+				errs[i] = &InstanceError{InstanceGUID: instance.GUID, Err: err}
+				return
+			}
+			for _, binding := range serviceInstances.BindingResources {
+				results[i] = append(results[i], InstanceBinding{BindingResource: binding, ServiceInstance: instance})
+			}
+		}(i, instance)
+	}
+	wg.Wait()
+
+	var bindings []InstanceBinding
+	var firstErr error
+	for i := range results {
+		bindings = append(bindings, results[i]...)
+		if firstErr == nil {
+			firstErr = errs[i]
+		}
+	}
+	return bindings, firstErr
+}
@@ -0,0 +1,79 @@
+package autoscaler_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"errors"
+
+	. "github.com/bijukunjummen/app-autoscaler-client"
+	"github.com/bijukunjummen/app-autoscaler-client/autoscalertest"
+)
+
+var _ = Describe("MultiClient", func() {
+	var server *autoscalertest.Server
+	var staging, production string
+
+	BeforeEach(func() {
+		server = autoscalertest.NewServer()
+		server.PageSize = 1
+		staging = server.NewServiceInstance("acme", "staging", "autoscaler")
+		production = server.NewServiceInstance("acme", "production", "autoscaler")
+		server.AddInstanceBinding(staging, Binding{AppName: "checkout-staging"})
+		server.AddInstanceBinding(production, Binding{AppName: "checkout"})
+		server.AddInstanceBinding(production, Binding{AppName: "cart"})
+		server.AddBinding(Binding{AppName: "unlisted"})
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Should discover every autoscaler service instance", func() {
+		multiClient, err := NewMultiClient(server.Config())
+
+		Ω(err).Should(BeNil())
+		Ω(multiClient.Instances()).Should(Equal([]ServiceInstance{
+			{GUID: staging, Name: "autoscaler", Org: "acme", Space: "staging"},
+			{GUID: production, Name: "autoscaler", Org: "acme", Space: "production"},
+		}))
+	})
+
+	It("Should aggregate the bindings of the service instances, tagged with their instance", func() {
+		multiClient, err := NewMultiClient(server.Config(), production, staging)
+		Ω(err).Should(BeNil())
+		multiClient.Concurrency = 1
+
+		bindings, err := multiClient.GetServiceBindings()
+
+		Ω(err).Should(BeNil())
+		var apps, spaces []string
+		for _, binding := range bindings {
+			apps = append(apps, binding.AppName)
+			spaces = append(spaces, binding.ServiceInstance.Space)
+		}
+		Ω(apps).Should(Equal([]string{"checkout", "cart", "checkout-staging"}))
+		Ω(spaces).Should(Equal([]string{"production", "production", "staging"}))
+		Ω(bindings[0].ServiceInstance.GUID).Should(Equal(production))
+	})
+
+	It("Should return the bindings of the instances answering along with the first failure", func() {
+		multiClient, err := NewMultiClient(server.Config())
+		Ω(err).Should(BeNil())
+		server.Token = "revoked"
+
+		bindings, err := multiClient.GetServiceBindings()
+
+		Ω(bindings).Should(BeEmpty())
+		var instanceError *InstanceError
+		Ω(errors.As(err, &instanceError)).Should(BeTrue())
+		Ω(instanceError.InstanceGUID).Should(Equal(staging))
+		Ω(IsUnauthorized(err)).Should(BeTrue())
+	})
+
+	It("Should fail on unknown service instances", func() {
+		_, err := NewMultiClient(server.Config(), "unknown")
+
+		Ω(IsNotFound(err)).Should(BeTrue())
+	})
+})
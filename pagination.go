@@ -0,0 +1,161 @@
+package autoscaler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+
+	"golang.org/x/net/context"
+)
+
+// ErrStopPaging can be returned by a PageFunc to stop walking through the pages without failing
+var ErrStopPaging = errors.New("stop paging")
+
+// ListOptions control how a list of resources is paged through
+type ListOptions struct {
+	// PageSize is the number of resources asked for in every page, the API default is used if not set
+	PageSize int
+}
+
+// Page is a single page of a list of resources, resources are left raw to be decoded by the caller
+type Page struct {
+	Resources  []json.RawMessage `json:"resources"`
+	Pagination *Pagination       `json:"pagination,omitempty"`
+	NextURL    string            `json:"next_url,omitempty"`
+}
+
+// PageFunc is called for every page of a list of resources, in order
+type PageFunc func(page *Page) error
+
+// ListPages walks through every page of the list of resources at listURL, following the next links of each page
+func (client *DefaultClient) ListPages(ctx context.Context, listURL string, options *ListOptions, fn PageFunc) error {
+	return client.walkPages(ctx, client.config.AutoscalerAPIUrl, listURL, options, fn)
+}
+
+// ListAll decodes the resources of every page of the list at listURL into out, which has to be a pointer to a slice
+func (client *DefaultClient) ListAll(ctx context.Context, listURL string, options *ListOptions, out interface{}) error {
+	return client.listAll(ctx, client.config.AutoscalerAPIUrl, listURL, options, out)
+}
+
+func (client *DefaultClient) listAll(ctx context.Context, baseURL, listURL string, options *ListOptions, out interface{}) error {
+	slice := reflect.ValueOf(out)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("Expected a pointer to a slice to list into, got %T", out)
+	}
+	items := slice.Elem()
+	itemType := items.Type().Elem()
+
+	return client.walkPages(ctx, baseURL, listURL, options, func(page *Page) error {
+		for _, resource := range page.Resources {
+			item := reflect.New(itemType)
+			if err := json.Unmarshal(resource, item.Interface()); err != nil {
+				return err
+			}
+			items.Set(reflect.Append(items, item.Elem()))
+		}
+		return nil
+	})
+}
+
+// walkPages walks through the pages of a list, hrefs to the next pages are resolved against baseURL
+func (client *DefaultClient) walkPages(ctx context.Context, baseURL, listURL string, options *ListOptions, fn PageFunc) error {
+	pageURL, err := withPageSize(listURL, options)
+	if err != nil {
+		return err
+	}
+	visited := make(map[string]bool)
+
+	for pageURL != "" {
+		if visited[pageURL] {
+			return fmt.Errorf("Pagination loop detected at %s", pageURL)
+		}
+		visited[pageURL] = true
+
+		page, err := client.getPage(ctx, pageURL)
+		if err != nil {
+			return err
+		}
+		if err = fn(page); err == ErrStopPaging {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		pageURL, err = resolveURL(baseURL, nextHref(page.Pagination, page.NextURL))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (client *DefaultClient) getPage(ctx context.Context, pageURL string) (*Page, error) {
+	request, err := client.httpClient.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, newAPIError(resp)
+	}
+
+	var page Page
+
+	decoder := json.NewDecoder(resp.Body)
+	if err = decoder.Decode(&page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+func (client *DefaultClient) listOptions() *ListOptions {
+	return &ListOptions{PageSize: client.config.PageSize}
+}
+
+// withPageSize adds the page size asked for to the url of the first page
+func withPageSize(listURL string, options *ListOptions) (string, error) {
+	if options == nil || options.PageSize <= 0 {
+		return listURL, nil
+	}
+	parsed, err := url.Parse(listURL)
+	if err != nil {
+		return "", err
+	}
+	query := parsed.Query()
+	query.Set("per_page", strconv.Itoa(options.PageSize))
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
+
+// resolveURL resolves a href returned by an API, which is typically a path like /api/bindings/...,
+// against the url of that API
+func resolveURL(baseURL, href string) (string, error) {
+	if href == "" {
+		return "", nil
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(ref).String(), nil
+}
+
+// nextHref returns the href to the next page of a list response, empty if on the last page
+func nextHref(pagination *Pagination, nextURL string) string {
+	if pagination != nil && pagination.Next != nil {
+		return pagination.Next.Href
+	}
+	return nextURL
+}
@@ -0,0 +1,126 @@
+package autoscaler_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"net/http"
+
+	. "github.com/bijukunjummen/app-autoscaler-client"
+	"github.com/onsi/gomega/ghttp"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Pagination", func() {
+	var server *ghttp.Server
+	var config *Config
+
+	bindingsPage1 := `
+	{
+	  "resources": [
+	    {"guid": "binding-1", "app_name": "app-1"},
+	    {"guid": "binding-2", "app_name": "app-2"}
+	  ],
+	  "next_url": "/api/instances/instanceid/bindings?page=2&per_page=2"
+	}`
+
+	bindingsPage2 := `
+	{
+	  "resources": [
+	    {"guid": "binding-3", "app_name": "app-3"}
+	  ],
+	  "next_url": null
+	}`
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v2/info"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, Endpoint{
+					AuthorizationEndpoint: server.URL(),
+					TokenEndpoint:         server.URL(),
+				}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/oauth/token"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, AccessToken{
+					Token: "test-token",
+				}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/instances/instanceid/bindings", "per_page=2"),
+				ghttp.RespondWith(http.StatusOK, bindingsPage1),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/instances/instanceid/bindings", "page=2&per_page=2"),
+				ghttp.RespondWith(http.StatusOK, bindingsPage2),
+			),
+		)
+		config = &Config{
+			CFConfig: &CFConfig{
+				CCApiURL:          server.URL(),
+				Username:          "user",
+				Password:          "pwd",
+				SkipSslValidation: true,
+			},
+			AutoscalerAPIUrl: server.URL() + "/api",
+			InstanceGUID:     "instanceid",
+			PageSize:         2,
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Should get the Service Bindings from every page", func() {
+		client, err := NewClient(config)
+		Ω(err).Should(BeNil())
+
+		serviceInstances, err := client.GetServiceBindings()
+
+		Ω(err).Should(BeNil())
+		Ω(len(serviceInstances.BindingResources)).Should(Equal(3))
+		Ω(serviceInstances.BindingResources[2].AppName).Should(Equal("app-3"))
+	})
+
+	It("Should walk through the pages until told to stop", func() {
+		client, err := NewClient(config)
+		Ω(err).Should(BeNil())
+
+		pages := 0
+		err = client.(*DefaultClient).ListPages(context.Background(), server.URL()+"/api/instances/instanceid/bindings",
+			&ListOptions{PageSize: 2}, func(page *Page) error {
+				pages++
+				Ω(len(page.Resources)).Should(Equal(2))
+				return ErrStopPaging
+			})
+
+		Ω(err).Should(BeNil())
+		Ω(pages).Should(Equal(1))
+	})
+
+	It("Should list everything into a slice", func() {
+		client, err := NewClient(config)
+		Ω(err).Should(BeNil())
+
+		var bindings []Binding
+		err = client.(*DefaultClient).ListAll(context.Background(), server.URL()+"/api/instances/instanceid/bindings",
+			&ListOptions{PageSize: 2}, &bindings)
+
+		Ω(err).Should(BeNil())
+		Ω(len(bindings)).Should(Equal(3))
+		Ω(bindings[0].GUID).Should(Equal("binding-1"))
+	})
+
+	It("Should refuse to list into anything but a slice", func() {
+		client, err := NewClient(config)
+		Ω(err).Should(BeNil())
+
+		var binding Binding
+		err = client.(*DefaultClient).ListAll(context.Background(), server.URL()+"/api/instances/instanceid/bindings", nil, &binding)
+
+		Ω(err).ShouldNot(BeNil())
+	})
+})
@@ -0,0 +1,406 @@
+package autoscaler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+	"gopkg.in/yaml.v2"
+)
+
+// Policy is the desired autoscaling configuration of a Binding, as kept in a YAML or JSON file like:
+//
+//	min_instances: 2
+//	max_instances: 10
+//	enabled: true
+//	rules:
+//	- type: cpu
+//	  enabled: true
+//	  min_threshold: 20
+//	  max_threshold: 80
+//	scheduled_limit_changes:
+//	- executes_at: "2017-01-02T09:00:00Z"
+//	  recurrence: weekdays
+//	  min_instances: 4
+//	  max_instances: 10
+//	  enabled: true
+//
+// Settings left out of the file are not managed: the instance limits when 0, the enabled flags when missing,
+// and the rules or scheduled limit changes when the whole list is missing. An empty list deletes every item.
+// Rules and scheduled limit changes created without an enabled flag are enabled.
+type Policy struct {
+	MinInstances          int                          `json:"min_instances,omitempty"`
+	MaxInstances          int                          `json:"max_instances,omitempty"`
+	Enabled               *bool                        `json:"enabled,omitempty"`
+	Rules                 []PolicyRule                 `json:"rules,omitempty"`
+	ScheduledLimitChanges []PolicyScheduledLimitChange `json:"scheduled_limit_changes,omitempty"`
+}
+
+// PolicyRule is a Rule of a Policy, its enabled flag only managed when set
+type PolicyRule struct {
+	Rule
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// PolicyScheduledLimitChange is a ScheduledLimitChange of a Policy, its enabled flag only managed when set
+type PolicyScheduledLimitChange struct {
+	ScheduledLimitChange
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// enabled returns the enabled flag of a policy item, the one of the live item when not set, true for a new item
+func enabled(desired *bool, live bool, exists bool) bool {
+	if desired != nil {
+		return *desired
+	}
+	return live || !exists
+}
+
+// Action is what a planned change does
+type Action string
+
+// The actions of a planned change
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// PlannedChange is a single change needed to bring a Binding in line with a Policy
+type PlannedChange struct {
+	Action Action
+	// Kind is one of "binding", "rule" or "scheduled_limit_change"
+	Kind        string
+	GUID        string
+	Description string
+
+	binding  *Binding
+	rule     *Rule
+	schedule *ScheduledLimitChange
+}
+
+// Plan lists the changes needed to bring a Binding in line with a Policy, in the order they are applied
+type Plan struct {
+	BindingGUID string
+	Changes     []PlannedChange
+}
+
+// LoadPolicy reads a Policy in YAML or JSON format
+func LoadPolicy(reader io.Reader) (*Policy, error) {
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var document interface{}
+	if err = yaml.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+	// the YAML document goes through JSON, for the policy to be read using the json tags of the API types
+	encoded, err := json.Marshal(fromYAML(document))
+	if err != nil {
+		return nil, err
+	}
+
+	var policy Policy
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("Invalid policy: %v", err)
+	}
+	return &policy, nil
+}
+
+// LoadPolicyFile reads a Policy from a YAML or JSON file
+func LoadPolicyFile(path string) (*Policy, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return LoadPolicy(file)
+}
+
+// fromYAML turns the maps decoded from YAML, keyed by interface{}, into maps which can be encoded to JSON
+func fromYAML(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			converted[fmt.Sprintf("%v", key)] = fromYAML(item)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(typed))
+		for i, item := range typed {
+			converted[i] = fromYAML(item)
+		}
+		return converted
+	case time.Time:
+		return typed.Format(time.RFC3339)
+	}
+	return value
+}
+
+// PlanPolicy computes the changes needed to bring a Binding in line with a Policy, without changing anything
+func PlanPolicy(client Client, bindingGUID string, policy *Policy) (*Plan, error) {
+	return PlanPolicyContext(context.Background(), client, bindingGUID, policy)
+}
+
+// PlanPolicyContext ...
+func PlanPolicyContext(ctx context.Context, client Client, bindingGUID string, policy *Policy) (*Plan, error) {
+	binding, err := client.GetBindingContext(ctx, bindingGUID)
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{BindingGUID: bindingGUID}
+	plan.planBinding(binding.Binding, policy)
+
+	if policy.Rules != nil {
+		plan.planRules(binding.Relationships.Rules, policy.Rules)
+	}
+
+	if policy.ScheduledLimitChanges != nil {
+		changes, err := client.GetScheduledLimitChangesContext(ctx, bindingGUID)
+		if err != nil {
+			return nil, err
+		}
+		plan.planScheduledLimitChanges(changes, policy.ScheduledLimitChanges)
+	}
+	return plan, nil
+}
+
+func (plan *Plan) planBinding(live Binding, policy *Policy) {
+	desired := live
+	desired.Relationships = Relationships{}
+	var differences []string
+
+	if policy.MinInstances != 0 && policy.MinInstances != live.MinInstances {
+		desired.MinInstances = policy.MinInstances
+		differences = append(differences, fmt.Sprintf("min_instances %d -> %d", live.MinInstances, policy.MinInstances))
+	}
+	if policy.MaxInstances != 0 && policy.MaxInstances != live.MaxInstances {
+		desired.MaxInstances = policy.MaxInstances
+		differences = append(differences, fmt.Sprintf("max_instances %d -> %d", live.MaxInstances, policy.MaxInstances))
+	}
+	if policy.Enabled != nil && *policy.Enabled != live.Enabled {
+		desired.Enabled = *policy.Enabled
+		differences = append(differences, fmt.Sprintf("enabled %t -> %t", live.Enabled, *policy.Enabled))
+	}
+
+	if len(differences) > 0 {
+		plan.Changes = append(plan.Changes, PlannedChange{
+			Action:      ActionUpdate,
+			Kind:        "binding",
+			GUID:        plan.BindingGUID,
+			Description: strings.Join(differences, ", "),
+			binding:     &desired,
+		})
+	}
+}
+
+// planRules matches rules by type and sub type, deleting live rules not in the policy
+func (plan *Plan) planRules(live []Rule, desired []PolicyRule) {
+	var creates, updates []PlannedChange
+	matched := make(map[int]bool)
+
+	for i := range desired {
+		rule := desired[i].Rule
+		index := -1
+		for j := range live {
+			if !matched[j] && live[j].Type == rule.Type && live[j].SubType == rule.SubType {
+				index = j
+				break
+			}
+		}
+		if index < 0 {
+			rule.Enabled = enabled(desired[i].Enabled, false, false)
+			creates = append(creates, PlannedChange{
+				Action:      ActionCreate,
+				Kind:        "rule",
+				Description: fmt.Sprintf("%s: %s", ruleName(rule), describeRule(rule)),
+				rule:        &rule,
+			})
+			continue
+		}
+		matched[index] = true
+
+		current := live[index]
+		rule.Enabled = enabled(desired[i].Enabled, current.Enabled, true)
+		if current.Enabled != rule.Enabled || current.MinThreshold != rule.MinThreshold || current.MaxThreshold != rule.MaxThreshold {
+			rule.GUID = current.GUID
+			updates = append(updates, PlannedChange{
+				Action:      ActionUpdate,
+				Kind:        "rule",
+				GUID:        current.GUID,
+				Description: fmt.Sprintf("%s: %s -> %s", ruleName(rule), describeRule(current), describeRule(rule)),
+				rule:        &rule,
+			})
+		}
+	}
+
+	for j := range live {
+		if !matched[j] {
+			plan.Changes = append(plan.Changes, PlannedChange{
+				Action:      ActionDelete,
+				Kind:        "rule",
+				GUID:        live[j].GUID,
+				Description: fmt.Sprintf("%s: %s", ruleName(live[j]), describeRule(live[j])),
+			})
+		}
+	}
+	plan.Changes = append(plan.Changes, updates...)
+	plan.Changes = append(plan.Changes, creates...)
+}
+
+// planScheduledLimitChanges matches scheduled limit changes by GUID when given in the policy, else by
+// the time they execute at and their recurrence, deleting live changes not in the policy
+func (plan *Plan) planScheduledLimitChanges(live []ScheduledLimitChange, desired []PolicyScheduledLimitChange) {
+	var creates, updates []PlannedChange
+	matched := make(map[int]bool)
+
+	for i := range desired {
+		change := desired[i].ScheduledLimitChange
+		index := -1
+		for j := range live {
+			if !matched[j] && sameScheduledLimitChange(live[j], change) {
+				index = j
+				break
+			}
+		}
+		if index < 0 {
+			change.GUID = ""
+			change.Enabled = enabled(desired[i].Enabled, false, false)
+			creates = append(creates, PlannedChange{
+				Action:      ActionCreate,
+				Kind:        "scheduled_limit_change",
+				Description: describeScheduledLimitChange(change),
+				schedule:    &change,
+			})
+			continue
+		}
+		matched[index] = true
+
+		current := live[index]
+		change.Enabled = enabled(desired[i].Enabled, current.Enabled, true)
+		if current.MinInstances != change.MinInstances || current.MaxInstances != change.MaxInstances ||
+			current.Enabled != change.Enabled || current.Recurrence != change.Recurrence || !sameTime(current.ExecutesAt, change.ExecutesAt) {
+			change.GUID = current.GUID
+			updates = append(updates, PlannedChange{
+				Action:      ActionUpdate,
+				Kind:        "scheduled_limit_change",
+				GUID:        current.GUID,
+				Description: fmt.Sprintf("%s -> %s", describeScheduledLimitChange(current), describeScheduledLimitChange(change)),
+				schedule:    &change,
+			})
+		}
+	}
+
+	for j := range live {
+		if !matched[j] {
+			plan.Changes = append(plan.Changes, PlannedChange{
+				Action:      ActionDelete,
+				Kind:        "scheduled_limit_change",
+				GUID:        live[j].GUID,
+				Description: describeScheduledLimitChange(live[j]),
+			})
+		}
+	}
+	plan.Changes = append(plan.Changes, updates...)
+	plan.Changes = append(plan.Changes, creates...)
+}
+
+// Empty returns true if the Binding is already in line with the Policy
+func (plan *Plan) Empty() bool {
+	return len(plan.Changes) == 0
+}
+
+// String lists the planned changes, one per line
+func (plan *Plan) String() string {
+	if plan.Empty() {
+		return fmt.Sprintf("No changes, binding %s is up to date\n", plan.BindingGUID)
+	}
+	symbols := map[Action]string{ActionCreate: "+", ActionUpdate: "~", ActionDelete: "-"}
+
+	var buffer bytes.Buffer
+	for _, change := range plan.Changes {
+		fmt.Fprintf(&buffer, "%s %s %s", symbols[change.Action], change.Action, change.Kind)
+		if change.GUID != "" {
+			fmt.Fprintf(&buffer, " %s", change.GUID)
+		}
+		fmt.Fprintf(&buffer, ": %s\n", change.Description)
+	}
+	return buffer.String()
+}
+
+// Apply makes the planned changes, in order, stopping at the first failure
+func (plan *Plan) Apply(client Client) error {
+	return plan.ApplyContext(context.Background(), client)
+}
+
+// ApplyContext ...
+func (plan *Plan) ApplyContext(ctx context.Context, client Client) error {
+	for _, change := range plan.Changes {
+		var err error
+		switch {
+		case change.Kind == "binding":
+			_, err = client.UpdateBindingEnabledContext(ctx, plan.BindingGUID, change.binding)
+		case change.Kind == "rule" && change.Action == ActionCreate:
+			_, err = client.CreateRuleContext(ctx, plan.BindingGUID, change.rule)
+		case change.Kind == "rule" && change.Action == ActionUpdate:
+			_, err = client.UpdateRuleContext(ctx, plan.BindingGUID, change.GUID, change.rule)
+		case change.Kind == "rule" && change.Action == ActionDelete:
+			err = client.DeleteRuleContext(ctx, plan.BindingGUID, change.GUID)
+		case change.Action == ActionCreate:
+			_, err = client.CreateScheduledLimitChangeContext(ctx, plan.BindingGUID, change.schedule)
+		case change.Action == ActionUpdate:
+			_, err = client.UpdateScheduledLimitChangeContext(ctx, plan.BindingGUID, change.GUID, change.schedule)
+		case change.Action == ActionDelete:
+			err = client.DeleteScheduledLimitChangeContext(ctx, plan.BindingGUID, change.GUID)
+		}
+		if err != nil {
+			return fmt.Errorf("Could not %s %s %s: %v", change.Action, change.Kind, change.Description, err)
+		}
+	}
+	return nil
+}
+
+func sameScheduledLimitChange(live, desired ScheduledLimitChange) bool {
+	if desired.GUID != "" {
+		return live.GUID == desired.GUID
+	}
+	return live.Recurrence == desired.Recurrence && sameTime(live.ExecutesAt, desired.ExecutesAt)
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func ruleName(rule Rule) string {
+	if rule.SubType != "" {
+		return rule.Type + "/" + rule.SubType
+	}
+	return rule.Type
+}
+
+func describeRule(rule Rule) string {
+	return fmt.Sprintf("thresholds %d-%d, enabled %t", rule.MinThreshold, rule.MaxThreshold, rule.Enabled)
+}
+
+func describeScheduledLimitChange(change ScheduledLimitChange) string {
+	executesAt := "-"
+	if change.ExecutesAt != nil {
+		executesAt = change.ExecutesAt.Format(time.RFC3339)
+	}
+	return fmt.Sprintf("at %s recurring %v, instances %d-%d, enabled %t",
+		executesAt, change.Recurrence, change.MinInstances, change.MaxInstances, change.Enabled)
+}
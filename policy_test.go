@@ -0,0 +1,173 @@
+package autoscaler_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"strings"
+	"time"
+
+	. "github.com/bijukunjummen/app-autoscaler-client"
+	"github.com/bijukunjummen/app-autoscaler-client/autoscalertest"
+)
+
+var _ = Describe("Policy", func() {
+	var fake *autoscalertest.FakeClient
+	var binding BindingResource
+	var cpuRule, memoryRule Rule
+	var morning, evening ScheduledLimitChange
+
+	samplePolicy := `
+min_instances: 3
+max_instances: 10
+enabled: true
+rules:
+- type: cpu
+  enabled: true
+  min_threshold: 30
+  max_threshold: 70
+- type: http_latency
+  sub_type: avg_95th
+  enabled: true
+  min_threshold: 100
+  max_threshold: 900
+scheduled_limit_changes:
+- executes_at: "2017-01-02T09:00:00Z"
+  recurrence: 62
+  min_instances: 4
+  max_instances: 10
+  enabled: true
+- executes_at: 2017-01-02T12:00:00Z
+  recurrence: 62
+  min_instances: 6
+  max_instances: 12
+  enabled: true
+`
+
+	BeforeEach(func() {
+		fake = autoscalertest.NewFakeClient()
+		binding = fake.AddBinding(BindingResource{
+			Binding: Binding{AppName: "checkout", MinInstances: 2, MaxInstances: 10, Enabled: true},
+		})
+		cpuRule = fake.AddRule(binding.GUID, Rule{Type: "cpu", Enabled: true, MinThreshold: 20, MaxThreshold: 80})
+		memoryRule = fake.AddRule(binding.GUID, Rule{Type: "memory", Enabled: true, MinThreshold: 20, MaxThreshold: 80})
+
+		morningAt, _ := time.Parse(time.RFC3339, "2017-01-02T09:00:00Z")
+		eveningAt, _ := time.Parse(time.RFC3339, "2017-01-02T18:00:00Z")
+		morning = fake.AddScheduledLimitChange(binding.GUID, ScheduledLimitChange{ExecutesAt: &morningAt, Recurrence: 62, MinInstances: 4, MaxInstances: 10, Enabled: true})
+		evening = fake.AddScheduledLimitChange(binding.GUID, ScheduledLimitChange{ExecutesAt: &eveningAt, Recurrence: 62, MinInstances: 2, MaxInstances: 10, Enabled: true})
+	})
+
+	It("Should load a policy in YAML format", func() {
+		policy, err := LoadPolicy(strings.NewReader(samplePolicy))
+
+		Ω(err).Should(BeNil())
+		Ω(policy.MinInstances).Should(Equal(3))
+		Ω(*policy.Enabled).Should(BeTrue())
+		Ω(len(policy.Rules)).Should(Equal(2))
+		Ω(policy.Rules[1].SubType).Should(Equal("avg_95th"))
+		Ω(len(policy.ScheduledLimitChanges)).Should(Equal(2))
+		Ω(policy.ScheduledLimitChanges[1].ExecutesAt.Hour()).Should(Equal(12))
+	})
+
+	It("Should load a policy in JSON format", func() {
+		policy, err := LoadPolicy(strings.NewReader(`{"max_instances": 8, "rules": []}`))
+
+		Ω(err).Should(BeNil())
+		Ω(policy.MaxInstances).Should(Equal(8))
+		Ω(policy.Enabled).Should(BeNil())
+		Ω(policy.Rules).ShouldNot(BeNil())
+		Ω(policy.ScheduledLimitChanges).Should(BeNil())
+	})
+
+	It("Should reject unknown settings", func() {
+		_, err := LoadPolicy(strings.NewReader(`max_instance: 8`))
+
+		Ω(err).ShouldNot(BeNil())
+	})
+
+	It("Should plan the changes without making them", func() {
+		policy, _ := LoadPolicy(strings.NewReader(samplePolicy))
+
+		plan, err := PlanPolicy(fake, binding.GUID, policy)
+
+		Ω(err).Should(BeNil())
+		var summary []string
+		for _, change := range plan.Changes {
+			summary = append(summary, string(change.Action)+" "+change.Kind+" "+change.GUID)
+		}
+		Ω(summary).Should(Equal([]string{
+			"update binding " + binding.GUID,
+			"delete rule " + memoryRule.GUID,
+			"update rule " + cpuRule.GUID,
+			"create rule ",
+			"delete scheduled_limit_change " + evening.GUID,
+			"create scheduled_limit_change ",
+		}))
+		Ω(plan.String()).Should(ContainSubstring("~ update binding " + binding.GUID + ": min_instances 2 -> 3"))
+		Ω(fake.CallCount("UpdateBindingEnabled")).Should(Equal(0))
+		Ω(fake.CallCount("DeleteRule")).Should(Equal(0))
+	})
+
+	It("Should apply the plan, leaving nothing more to change", func() {
+		policy, _ := LoadPolicy(strings.NewReader(samplePolicy))
+		plan, _ := PlanPolicy(fake, binding.GUID, policy)
+
+		Ω(plan.Apply(fake)).Should(Succeed())
+
+		updated, _ := fake.GetBinding(binding.GUID)
+		Ω(updated.MinInstances).Should(Equal(3))
+		rules, _ := fake.GetRules(binding.GUID)
+		Ω(len(rules)).Should(Equal(2))
+		changes, _ := fake.GetScheduledLimitChanges(binding.GUID)
+		Ω(len(changes)).Should(Equal(2))
+		Ω(changes[0].GUID).Should(Equal(morning.GUID))
+
+		replan, err := PlanPolicy(fake, binding.GUID, policy)
+		Ω(err).Should(BeNil())
+		Ω(replan.Empty()).Should(BeTrue(), replan.String())
+	})
+	It("Should turn autoscaling off", func() {
+		policy, _ := LoadPolicy(strings.NewReader(`enabled: false`))
+		plan, _ := PlanPolicy(fake, binding.GUID, policy)
+
+		Ω(plan.Apply(fake)).Should(Succeed())
+
+		updated, _ := fake.GetBinding(binding.GUID)
+		Ω(updated.Enabled).Should(BeFalse())
+		Ω(fake.CallCount("UpdateBindingEnabled")).Should(Equal(1))
+	})
+	It("Should leave the enabled flags out of the policy as they are, enabling what it creates", func() {
+		fake.UpdateRule(binding.GUID, cpuRule.GUID, &Rule{Type: "cpu", Enabled: false, MinThreshold: 20, MaxThreshold: 80})
+		policy, _ := LoadPolicy(strings.NewReader(`
+rules:
+- type: cpu
+  min_threshold: 20
+  max_threshold: 80
+- type: memory
+  min_threshold: 20
+  max_threshold: 80
+- type: http_throughput
+  min_threshold: 10
+  max_threshold: 50
+scheduled_limit_changes:
+- executes_at: "2017-01-02T09:00:00Z"
+  recurrence: 62
+  min_instances: 4
+  max_instances: 10
+- executes_at: "2017-01-02T18:00:00Z"
+  recurrence: 62
+  min_instances: 2
+  max_instances: 10
+  enabled: false
+`))
+
+		plan, err := PlanPolicy(fake, binding.GUID, policy)
+
+		Ω(err).Should(BeNil())
+		Ω(plan.String()).Should(Equal(
+			"+ create rule: http_throughput: thresholds 10-50, enabled true\n" +
+				"~ update scheduled_limit_change " + evening.GUID + ": at 2017-01-02T18:00:00Z recurring weekdays, instances 2-10, enabled true" +
+				" -> at 2017-01-02T18:00:00Z recurring weekdays, instances 2-10, enabled false\n"))
+	})
+})
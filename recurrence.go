@@ -0,0 +1,178 @@
+package autoscaler
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence is the bitmask of the days of the week a ScheduledLimitChange recurs on,
+// with one bit per time.Weekday: 1 for Sunday, 2 for Monday, up to 64 for Saturday
+type Recurrence int
+
+// The days of the week a ScheduledLimitChange can recur on
+const (
+	Sunday Recurrence = 1 << iota
+	Monday
+	Tuesday
+	Wednesday
+	Thursday
+	Friday
+	Saturday
+
+	Weekdays = Monday | Tuesday | Wednesday | Thursday | Friday
+	Weekend  = Saturday | Sunday
+	EveryDay = Weekdays | Weekend
+)
+
+var dayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+var recurrenceNames = map[string]Recurrence{
+	"weekdays": Weekdays,
+	"weekend":  Weekend,
+	"weekends": Weekend,
+	"daily":    EveryDay,
+	"everyday": EveryDay,
+	"none":     0,
+}
+
+// RecurrenceOf returns the Recurrence on the given days
+func RecurrenceOf(days ...time.Weekday) Recurrence {
+	var recurrence Recurrence
+	for _, day := range days {
+		recurrence = recurrence.Set(day)
+	}
+	return recurrence
+}
+
+// Has returns true if the Recurrence includes the day
+func (recurrence Recurrence) Has(day time.Weekday) bool {
+	return recurrence&dayBit(day) != 0
+}
+
+// Set returns the Recurrence with the day added
+func (recurrence Recurrence) Set(day time.Weekday) Recurrence {
+	return recurrence | dayBit(day)
+}
+
+// Clear returns the Recurrence with the day removed
+func (recurrence Recurrence) Clear(day time.Weekday) Recurrence {
+	return recurrence &^ dayBit(day)
+}
+
+// Days lists the days of the Recurrence, from Sunday to Saturday
+func (recurrence Recurrence) Days() []time.Weekday {
+	var days []time.Weekday
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if recurrence.Has(day) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// Valid returns true if the Recurrence has no bits set beyond the days of the week
+func (recurrence Recurrence) Valid() bool {
+	return recurrence&^EveryDay == 0
+}
+
+// String returns the days of the Recurrence like "mon,wed,fri", or one of "weekdays", "weekend", "daily" and "none"
+func (recurrence Recurrence) String() string {
+	switch recurrence {
+	case 0:
+		return "none"
+	case Weekdays:
+		return "weekdays"
+	case Weekend:
+		return "weekend"
+	case EveryDay:
+		return "daily"
+	}
+	if !recurrence.Valid() {
+		return strconv.Itoa(int(recurrence))
+	}
+	var names []string
+	for _, day := range recurrence.Days() {
+		names = append(names, dayNames[day])
+	}
+	return strings.Join(names, ",")
+}
+
+// ParseRecurrence parses a comma separated list of days or ranges of days, like "mon,tue,fri" or "mon-fri,sun",
+// where days are given by their short or full English names. "weekdays", "weekend", "daily" and "none"
+// are accepted too, as well as the bitmask as a number.
+func ParseRecurrence(value string) (Recurrence, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if number, err := strconv.Atoi(value); err == nil {
+		recurrence := Recurrence(number)
+		if !recurrence.Valid() {
+			return 0, fmt.Errorf("Invalid recurrence %d, expected a bitmask between 0 and %d", number, EveryDay)
+		}
+		return recurrence, nil
+	}
+
+	var recurrence Recurrence
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if named, ok := recurrenceNames[part]; ok {
+			recurrence |= named
+			continue
+		}
+
+		bounds := strings.SplitN(part, "-", 2)
+		first, err := parseDay(bounds[0])
+		if err != nil {
+			return 0, err
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = parseDay(bounds[1]); err != nil {
+				return 0, err
+			}
+		}
+		for day := first; ; day = (day + 1) % 7 {
+			recurrence = recurrence.Set(day)
+			if day == last {
+				break
+			}
+		}
+	}
+	return recurrence, nil
+}
+
+// UnmarshalJSON accepts the bitmask as sent by the API, as well as any string accepted by ParseRecurrence
+func (recurrence *Recurrence) UnmarshalJSON(data []byte) error {
+	var number int
+	if err := json.Unmarshal(data, &number); err == nil {
+		*recurrence = Recurrence(number)
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("Invalid recurrence %s, expected a number or a list of days", data)
+	}
+	parsed, err := ParseRecurrence(value)
+	if err != nil {
+		return err
+	}
+	*recurrence = parsed
+	return nil
+}
+
+func parseDay(name string) (time.Weekday, error) {
+	name = strings.TrimSpace(name)
+	if len(name) >= 3 {
+		for day, dayName := range dayNames {
+			if strings.HasPrefix(name, dayName) && strings.HasPrefix(strings.ToLower(time.Weekday(day).String()), name) {
+				return time.Weekday(day), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("Invalid day of the week %q", name)
+}
+
+func dayBit(day time.Weekday) Recurrence {
+	return Recurrence(1) << uint(day)
+}
@@ -0,0 +1,74 @@
+package autoscaler_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"encoding/json"
+	"time"
+
+	. "github.com/bijukunjummen/app-autoscaler-client"
+)
+
+var _ = Describe("Recurrence", func() {
+
+	It("Should have one bit per day of the week", func() {
+		recurrence := RecurrenceOf(time.Monday, time.Wednesday, time.Friday)
+
+		Ω(recurrence).Should(Equal(Monday | Wednesday | Friday))
+		Ω(int(recurrence)).Should(Equal(42))
+		Ω(recurrence.Has(time.Wednesday)).Should(BeTrue())
+		Ω(recurrence.Has(time.Sunday)).Should(BeFalse())
+		Ω(recurrence.Set(time.Sunday).Clear(time.Friday)).Should(Equal(Sunday | Monday | Wednesday))
+		Ω(recurrence.Days()).Should(Equal([]time.Weekday{time.Monday, time.Wednesday, time.Friday}))
+		Ω(int(Weekdays)).Should(Equal(62))
+		Ω(int(EveryDay)).Should(Equal(127))
+	})
+
+	It("Should print as a list of days", func() {
+		Ω(RecurrenceOf(time.Monday, time.Tuesday, time.Friday).String()).Should(Equal("mon,tue,fri"))
+		Ω(Weekdays.String()).Should(Equal("weekdays"))
+		Ω(Recurrence(0).String()).Should(Equal("none"))
+		Ω(Recurrence(200).String()).Should(Equal("200"))
+	})
+
+	It("Should parse lists and ranges of days", func() {
+		parsed := func(value string) Recurrence {
+			recurrence, err := ParseRecurrence(value)
+			Ω(err).Should(BeNil())
+			return recurrence
+		}
+
+		Ω(parsed("mon,tue,fri")).Should(Equal(Monday | Tuesday | Friday))
+		Ω(parsed("Monday, Thurs")).Should(Equal(Monday | Thursday))
+		Ω(parsed("weekdays")).Should(Equal(Weekdays))
+		Ω(parsed("mon-fri")).Should(Equal(Weekdays))
+		Ω(parsed("fri-mon")).Should(Equal(Friday | Weekend | Monday))
+		Ω(parsed("weekend,wed")).Should(Equal(Weekend | Wednesday))
+		Ω(parsed("20")).Should(Equal(Recurrence(20)))
+	})
+
+	It("Should reject unknown days", func() {
+		_, err := ParseRecurrence("mon,funday")
+		Ω(err).ShouldNot(BeNil())
+
+		_, err = ParseRecurrence("mo")
+		Ω(err).ShouldNot(BeNil())
+
+		_, err = ParseRecurrence("128")
+		Ω(err).ShouldNot(BeNil())
+	})
+
+	It("Should stay an integer on the wire", func() {
+		encoded, err := json.Marshal(ScheduledLimitChange{Recurrence: Weekdays})
+		Ω(err).Should(BeNil())
+		Ω(string(encoded)).Should(ContainSubstring(`"recurrence":62`))
+
+		var change ScheduledLimitChange
+		Ω(json.Unmarshal([]byte(`{"recurrence": 20}`), &change)).Should(Succeed())
+		Ω(change.Recurrence).Should(Equal(Tuesday | Thursday))
+
+		Ω(json.Unmarshal([]byte(`{"recurrence": "sat,sun"}`), &change)).Should(Succeed())
+		Ω(change.Recurrence).Should(Equal(Weekend))
+	})
+})
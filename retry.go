@@ -0,0 +1,180 @@
+package autoscaler
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy configures how calls to the Autoscaler API are retried on transient failures
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made for a call, including the first one
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry, doubled for every further retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between two attempts, including waits asked for through Retry-After
+	MaxBackoff time.Duration
+	// Jitter is the fraction, between 0 and 1, by which every wait is randomly shortened
+	Jitter float64
+	// RetryNonIdempotent allows POST calls to be retried too, which may create duplicates
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy - default policy for retrying calls to the Autoscaler API
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Jitter:         0.2,
+	}
+}
+
+// do makes the call, retrying it as per the retry policy of the client
+func (client *DefaultClient) do(request *http.Request) (*http.Response, error) {
+	policy := client.config.RetryPolicy
+	if policy == nil || policy.MaxAttempts <= 1 || !policy.retries(request.Method) {
+		return client.httpClient.Do(request)
+	}
+
+	if err := bufferBody(request); err != nil {
+		return nil, err
+	}
+
+	ctx := request.Context()
+	for attempt := 1; ; attempt++ {
+		attemptRequest, err := replay(request, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := client.httpClient.Do(attemptRequest)
+		if attempt >= policy.MaxAttempts || ctx.Err() != nil || !isTransient(resp, err) {
+			return resp, err
+		}
+
+		wait := policy.backoff(attempt, resp)
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (policy *RetryPolicy) retries(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
+	case "POST":
+		return policy.RetryNonIdempotent
+	}
+	return false
+}
+
+// backoff returns the wait before the next attempt, honoring Retry-After on 429 and 503 responses
+func (policy *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	wait := policy.InitialBackoff << uint(attempt-1)
+	if wait < policy.InitialBackoff {
+		wait = policy.MaxBackoff
+	}
+
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			wait = retryAfter
+		}
+	} else if policy.Jitter > 0 {
+		wait -= time.Duration(rand.Float64() * policy.Jitter * float64(wait))
+	}
+
+	if policy.MaxBackoff > 0 && wait > policy.MaxBackoff {
+		wait = policy.MaxBackoff
+	}
+	return wait
+}
+
+// isTransient returns true for the responses and errors worth another attempt
+func isTransient(resp *http.Response, err error) bool {
+	if err != nil {
+		return isNetworkError(err)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isNetworkError returns true for connections reset, refused or cut short and for timeouts, not for the errors
+// another attempt would fail with again, like certificate verification or token failures
+func isNetworkError(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// parseRetryAfter parses a Retry-After header, given either in seconds or as an http date
+func parseRetryAfter(retryAfter string) (time.Duration, bool) {
+	if retryAfter == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(retryAfter); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// bufferBody reads a request body which cannot be replayed into memory, so that it can be sent again
+func bufferBody(request *http.Request) error {
+	if request.Body == nil || request.GetBody != nil {
+		return nil
+	}
+	body, err := ioutil.ReadAll(request.Body)
+	request.Body.Close()
+	if err != nil {
+		return err
+	}
+	request.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+	request.Body, _ = request.GetBody()
+	return nil
+}
+
+// replay returns the request to be sent for an attempt, with a fresh copy of the body for retries
+func replay(request *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 1 || request.Body == nil || request.GetBody == nil {
+		return request, nil
+	}
+	body, err := request.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	attemptRequest := request.Clone(request.Context())
+	attemptRequest.Body = body
+	return attemptRequest, nil
+}
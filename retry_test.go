@@ -0,0 +1,177 @@
+package autoscaler_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/bijukunjummen/app-autoscaler-client"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Retries", func() {
+	var server *ghttp.Server
+	var config *Config
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v2/info"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, Endpoint{
+					AuthorizationEndpoint: server.URL(),
+					TokenEndpoint:         server.URL(),
+				}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/oauth/token"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, AccessToken{
+					Token: "test-token",
+				}),
+			),
+		)
+		config = &Config{
+			CFConfig: &CFConfig{
+				CCApiURL:          server.URL(),
+				Username:          "user",
+				Password:          "pwd",
+				SkipSslValidation: true,
+			},
+			AutoscalerAPIUrl: server.URL() + "/api",
+			InstanceGUID:     "instanceid",
+			RetryPolicy: &RetryPolicy{
+				MaxAttempts:    3,
+				InitialBackoff: time.Millisecond,
+				MaxBackoff:     10 * time.Millisecond,
+				Jitter:         0.5,
+			},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Should retry an idempotent call failing transiently, sending the same body again", func() {
+		verifyBody := func(w http.ResponseWriter, req *http.Request) {
+			body, err := ioutil.ReadAll(req.Body)
+			Ω(err).Should(BeNil())
+			Ω(string(body)).Should(ContainSubstring(`"app_name":"myapp"`))
+		}
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", "/api/bindings/mybinding"),
+				verifyBody,
+				ghttp.RespondWith(http.StatusBadGateway, "Bad Gateway"),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", "/api/bindings/mybinding"),
+				verifyBody,
+				ghttp.RespondWith(http.StatusServiceUnavailable, "Unavailable", http.Header{"Retry-After": []string{"120"}}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", "/api/bindings/mybinding"),
+				verifyBody,
+				ghttp.RespondWith(http.StatusOK, `{"guid": "mybinding", "app_name": "myapp"}`),
+			),
+		)
+		client, err := NewClient(config)
+		Ω(err).Should(BeNil())
+
+		binding, err := client.UpdateBinding("mybinding", &Binding{AppName: "myapp"})
+
+		Ω(err).Should(BeNil())
+		Ω(binding.GUID).Should(Equal("mybinding"))
+		Ω(len(server.ReceivedRequests())).Should(Equal(5))
+	})
+
+	It("Should give up after the maximum number of attempts", func() {
+		server.AppendHandlers(
+			ghttp.RespondWith(http.StatusGatewayTimeout, "Timeout"),
+			ghttp.RespondWith(http.StatusGatewayTimeout, "Timeout"),
+			ghttp.RespondWith(http.StatusGatewayTimeout, "Timeout"),
+		)
+		client, err := NewClient(config)
+		Ω(err).Should(BeNil())
+
+		_, err = client.GetBinding("mybinding")
+
+		Ω(err.(*APIError).StatusCode).Should(Equal(http.StatusGatewayTimeout))
+		Ω(len(server.ReceivedRequests())).Should(Equal(5))
+	})
+
+	It("Should not retry a POST call by default", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/api/bindings/mybinding/rules"),
+				ghttp.RespondWith(http.StatusServiceUnavailable, "Unavailable"),
+			),
+		)
+		client, err := NewClient(config)
+		Ω(err).Should(BeNil())
+
+		_, err = client.CreateRule("mybinding", &Rule{Type: "cpu"})
+
+		Ω(err.(*APIError).StatusCode).Should(Equal(http.StatusServiceUnavailable))
+		Ω(len(server.ReceivedRequests())).Should(Equal(3))
+	})
+
+	It("Should not retry a call failing with a client error", func() {
+		server.AppendHandlers(
+			ghttp.RespondWith(http.StatusNotFound, "Not Found"),
+		)
+		client, err := NewClient(config)
+		Ω(err).Should(BeNil())
+
+		_, err = client.GetBinding("mybinding")
+
+		Ω(IsNotFound(err)).Should(BeTrue())
+		Ω(len(server.ReceivedRequests())).Should(Equal(3))
+	})
+	It("Should retry a call whose connection is cut short", func() {
+		var attempts int
+		server.RouteToHandler("GET", "/api/bindings/mybinding", func(w http.ResponseWriter, req *http.Request) {
+			attempts++
+			if attempts < 3 {
+				conn, _, err := w.(http.Hijacker).Hijack()
+				Ω(err).Should(BeNil())
+				conn.Close()
+				return
+			}
+			w.Write([]byte(`{"guid": "mybinding"}`))
+		})
+		client, err := NewClient(config)
+		Ω(err).Should(BeNil())
+
+		binding, err := client.GetBinding("mybinding")
+
+		Ω(err).Should(BeNil())
+		Ω(binding.GUID).Should(Equal("mybinding"))
+	})
+
+	It("Should not retry a call failing certificate verification", func() {
+		var connections int
+		tlsServer := httptest.NewUnstartedServer(http.NotFoundHandler())
+		tlsServer.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				connections++
+			}
+		}
+		tlsServer.StartTLS()
+		defer tlsServer.Close()
+		config.CFConfig.SkipSslValidation = false
+		config.AutoscalerAPIUrl = tlsServer.URL + "/api"
+		client, err := NewClient(config)
+		Ω(err).Should(BeNil())
+
+		_, err = client.GetBinding("mybinding")
+
+		Ω(err).Should(MatchError(ContainSubstring("certificate")))
+		Ω(connections).Should(Equal(1))
+	})
+})
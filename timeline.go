@@ -0,0 +1,117 @@
+package autoscaler
+
+import (
+	"sort"
+	"time"
+)
+
+// LimitPeriod is a period of time over which the same instance limits apply to a Binding
+type LimitPeriod struct {
+	Start        time.Time
+	End          time.Time
+	MinInstances int
+	MaxInstances int
+	// Change is the scheduled limit change which set the limits at Start, nil for the limits of the Binding itself
+	Change *ScheduledLimitChange
+}
+
+// limitChangeOccurrence is a point in time a scheduled limit change executes at
+type limitChangeOccurrence struct {
+	at     time.Time
+	change *ScheduledLimitChange
+}
+
+// Timeline expands the scheduled limit changes of a Binding into the ordered periods of effective instance limits
+// between from and to. A change without recurrence executes once at ExecutesAt, a recurring change executes on
+// every day of its recurrence at the time of day of ExecutesAt, in the location of ExecutesAt, starting from
+// ExecutesAt. The limits in effect at from are those set by the last change executed until then, or the limits
+// of the Binding if none has. Of changes executing at the same time, the last one listed sets the limits.
+// A period ends when the limits change, executions of changes setting the same limits again do not end it.
+// Disabled changes and changes without ExecutesAt are ignored.
+func Timeline(binding *Binding, changes []ScheduledLimitChange, from, to time.Time) []LimitPeriod {
+	if !to.After(from) {
+		return nil
+	}
+
+	var occurrences []limitChangeOccurrence
+	for i := range changes {
+		occurrences = append(occurrences, limitChangeOccurrences(&changes[i], from, to)...)
+	}
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].at.Before(occurrences[j].at)
+	})
+
+	current := LimitPeriod{
+		Start:        from,
+		MinInstances: binding.MinInstances,
+		MaxInstances: binding.MaxInstances,
+	}
+	var periods []LimitPeriod
+
+	for _, occurrence := range occurrences {
+		if occurrence.at.After(current.Start) {
+			current.End = occurrence.at
+			periods = append(periods, current)
+			current.Start = occurrence.at
+		}
+		current.MinInstances = occurrence.change.MinInstances
+		current.MaxInstances = occurrence.change.MaxInstances
+		current.Change = occurrence.change
+	}
+
+	current.End = to
+	return mergeLimitPeriods(append(periods, current))
+}
+
+// mergeLimitPeriods merges the adjacent periods setting the same limits
+func mergeLimitPeriods(periods []LimitPeriod) []LimitPeriod {
+	merged := periods[:1]
+	for _, period := range periods[1:] {
+		last := &merged[len(merged)-1]
+		if period.MinInstances == last.MinInstances && period.MaxInstances == last.MaxInstances {
+			last.End = period.End
+			continue
+		}
+		merged = append(merged, period)
+	}
+	return merged
+}
+
+// EffectiveLimits returns the instance limits in effect for a Binding at a point in time,
+// as a LimitPeriod lasting until a scheduled limit change next changes them, if any before end of the following week
+func EffectiveLimits(binding *Binding, changes []ScheduledLimitChange, at time.Time) LimitPeriod {
+	return Timeline(binding, changes, at, at.AddDate(0, 0, 8))[0]
+}
+
+// limitChangeOccurrences lists the times a change executes at before to, including the last time it
+// executed at until from, so that the limits in effect at from can be told
+func limitChangeOccurrences(change *ScheduledLimitChange, from, to time.Time) []limitChangeOccurrence {
+	if !change.Enabled || change.ExecutesAt == nil || !change.ExecutesAt.Before(to) {
+		return nil
+	}
+	executesAt := *change.ExecutesAt
+
+	if change.Recurrence == 0 {
+		return []limitChangeOccurrence{{at: executesAt, change: change}}
+	}
+
+	// a week before from holds the last execution of the recurring change until from, if any
+	start := executesAt
+	if weekBefore := from.AddDate(0, 0, -8); weekBefore.After(start) {
+		start = weekBefore.In(executesAt.Location())
+	}
+
+	var occurrences []limitChangeOccurrence
+	for day := 0; ; day++ {
+		at := time.Date(start.Year(), start.Month(), start.Day()+day,
+			executesAt.Hour(), executesAt.Minute(), executesAt.Second(), executesAt.Nanosecond(), executesAt.Location())
+		if !at.Before(to) {
+			break
+		}
+		if at.Before(executesAt) || !change.Recurrence.Has(at.Weekday()) {
+			continue
+		}
+		occurrences = append(occurrences, limitChangeOccurrence{at: at, change: change})
+	}
+	return occurrences
+}
@@ -0,0 +1,105 @@
+package autoscaler_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"time"
+
+	. "github.com/bijukunjummen/app-autoscaler-client"
+)
+
+var _ = Describe("Timeline", func() {
+	var binding *Binding
+	var changes []ScheduledLimitChange
+
+	at := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		Ω(err).Should(BeNil())
+		return parsed
+	}
+	pointer := func(value time.Time) *time.Time {
+		return &value
+	}
+
+	BeforeEach(func() {
+		binding = &Binding{MinInstances: 2, MaxInstances: 5}
+		// 2017-01-02 is a Monday
+		changes = []ScheduledLimitChange{
+			{GUID: "morning", ExecutesAt: pointer(at("2017-01-02T09:00:00Z")), Recurrence: Weekdays, MinInstances: 4, MaxInstances: 10, Enabled: true},
+			{GUID: "evening", ExecutesAt: pointer(at("2017-01-02T18:00:00Z")), Recurrence: Weekdays, MinInstances: 2, MaxInstances: 5, Enabled: true},
+			{GUID: "launch", ExecutesAt: pointer(at("2017-01-10T12:00:00Z")), MinInstances: 20, MaxInstances: 40, Enabled: true},
+			{GUID: "disabled", ExecutesAt: pointer(at("2017-01-10T10:00:00Z")), Recurrence: EveryDay, MinInstances: 1, MaxInstances: 1},
+		}
+	})
+
+	It("Should start with the limits of the binding before any change executed", func() {
+		periods := Timeline(binding, changes, at("2017-01-01T00:00:00Z"), at("2017-01-02T12:00:00Z"))
+
+		Ω(len(periods)).Should(Equal(2))
+		Ω(periods[0].Change).Should(BeNil())
+		Ω(periods[0].MinInstances).Should(Equal(2))
+		Ω(periods[0].End).Should(Equal(at("2017-01-02T09:00:00Z")))
+		Ω(periods[1].Change.GUID).Should(Equal("morning"))
+		Ω(periods[1].MaxInstances).Should(Equal(10))
+		Ω(periods[1].End).Should(Equal(at("2017-01-02T12:00:00Z")))
+	})
+
+	It("Should expand the recurring and one off changes over a window", func() {
+		periods := Timeline(binding, changes, at("2017-01-10T00:00:00Z"), at("2017-01-11T00:00:00Z"))
+
+		var guids []string
+		for _, period := range periods {
+			guids = append(guids, period.Change.GUID)
+		}
+		Ω(guids).Should(Equal([]string{"evening", "morning", "launch", "evening"}))
+		Ω(periods[2].Start).Should(Equal(at("2017-01-10T12:00:00Z")))
+		Ω(periods[2].MinInstances).Should(Equal(20))
+		Ω(periods[3].End).Should(Equal(at("2017-01-11T00:00:00Z")))
+	})
+
+	It("Should skip the days the changes do not recur on", func() {
+		// 2017-01-07 is a Saturday, the Friday evening limits apply over the weekend
+		periods := Timeline(binding, changes, at("2017-01-07T00:00:00Z"), at("2017-01-09T10:00:00Z"))
+
+		Ω(len(periods)).Should(Equal(2))
+		Ω(periods[0].Change.GUID).Should(Equal("evening"))
+		Ω(periods[0].End).Should(Equal(at("2017-01-09T09:00:00Z")))
+	})
+
+	It("Should tell the limits in effect at a point in time", func() {
+		limits := EffectiveLimits(binding, changes, at("2017-01-17T09:00:00Z"))
+
+		Ω(limits.Change.GUID).Should(Equal("morning"))
+		Ω(limits.MinInstances).Should(Equal(4))
+		Ω(limits.MaxInstances).Should(Equal(10))
+		Ω(limits.End).Should(Equal(at("2017-01-17T18:00:00Z")))
+	})
+
+	It("Should apply the last of the changes executing at the same time", func() {
+		changes = append(changes, ScheduledLimitChange{GUID: "sale", ExecutesAt: pointer(at("2017-01-03T09:00:00Z")), MinInstances: 5, MaxInstances: 6, Enabled: true})
+
+		periods := Timeline(binding, changes, at("2017-01-03T08:00:00Z"), at("2017-01-03T10:00:00Z"))
+
+		Ω(len(periods)).Should(Equal(2))
+		Ω(periods[0].End).Should(Equal(at("2017-01-03T09:00:00Z")))
+		Ω(periods[1].Start).Should(Equal(at("2017-01-03T09:00:00Z")))
+		Ω(periods[1].Change.GUID).Should(Equal("sale"))
+		Ω(periods[1].MinInstances).Should(Equal(5))
+	})
+
+	It("Should not end a period on a change setting the same limits again", func() {
+		changes = changes[:1]
+
+		periods := Timeline(binding, changes, at("2017-01-02T00:00:00Z"), at("2017-01-05T00:00:00Z"))
+
+		Ω(len(periods)).Should(Equal(2))
+		Ω(periods[1].Change.GUID).Should(Equal("morning"))
+		Ω(periods[1].Start).Should(Equal(at("2017-01-02T09:00:00Z")))
+		Ω(periods[1].End).Should(Equal(at("2017-01-05T00:00:00Z")))
+	})
+
+	It("Should be empty for an empty window", func() {
+		Ω(Timeline(binding, changes, at("2017-01-10T00:00:00Z"), at("2017-01-10T00:00:00Z"))).Should(BeEmpty())
+	})
+})
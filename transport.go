@@ -0,0 +1,175 @@
+package autoscaler
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// newTransport builds the transport every call to Cloud Controller, UAA and the Autoscaler API goes through,
+// the oauth2 layer adding the token on top of it. The TLS and proxy settings of the config are applied to a copy
+// of the Transport of the config, or of the default transport if not set.
+func newTransport(config *CFConfig) (http.RoundTripper, error) {
+	base := config.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	transport, ok := base.(*http.Transport)
+	if !ok {
+		if hasTLSSettings(config) || config.ProxyURL != "" || config.NoProxy != "" {
+			return nil, fmt.Errorf("Cannot apply the TLS and proxy settings to a Transport of type %T", base)
+		}
+		return base, nil
+	}
+	transport = transport.Clone()
+
+	if hasTLSSettings(config) || config.Transport == nil {
+		tlsConfig, err := newTLSConfig(config, transport.TLSClientConfig)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+	if config.ProxyURL != "" || config.NoProxy != "" {
+		proxyConfig := &httpproxy.Config{
+			HTTPProxy:  config.ProxyURL,
+			HTTPSProxy: config.ProxyURL,
+			NoProxy:    config.NoProxy,
+		}
+		if config.ProxyURL == "" {
+			fromEnv := httpproxy.FromEnvironment()
+			proxyConfig.HTTPProxy, proxyConfig.HTTPSProxy = fromEnv.HTTPProxy, fromEnv.HTTPSProxy
+		}
+		proxyFunc := proxyConfig.ProxyFunc()
+		transport.Proxy = func(request *http.Request) (*url.URL, error) {
+			return proxyFunc(request.URL)
+		}
+	}
+	return transport, nil
+}
+
+// hasTLSSettings returns true if the config sets any TLS setting
+func hasTLSSettings(config *CFConfig) bool {
+	return config.SkipSslValidation || config.CACertFile != "" || len(config.CACerts) > 0 ||
+		config.ClientCertFile != "" || config.ClientKeyFile != "" || len(config.ClientCert) > 0 || len(config.ClientKey) > 0
+}
+
+// newTLSConfig adds the TLS settings of the config to a copy of the base TLS config, if any: it trusts the CA
+// certificates of the config on top of the root CAs of the base, else of the system ones unless told otherwise,
+// and presents the client certificate of the config if any
+func newTLSConfig(config *CFConfig, base *tls.Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+	if base != nil {
+		tlsConfig = base.Clone()
+	}
+	tlsConfig.InsecureSkipVerify = tlsConfig.InsecureSkipVerify || config.SkipSslValidation
+
+	switch {
+	case config.ClientCertFile != "" || config.ClientKeyFile != "":
+		reloader, err := newCertificateReloader(config.ClientCertFile, config.ClientKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.GetClientCertificate = reloader.GetClientCertificate
+	case len(config.ClientCert) > 0 || len(config.ClientKey) > 0:
+		certificate, err := tls.X509KeyPair(config.ClientCert, config.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	if config.CACertFile == "" && len(config.CACerts) == 0 {
+		return tlsConfig, nil
+	}
+
+	pool := x509.NewCertPool()
+	if tlsConfig.RootCAs != nil {
+		pool = tlsConfig.RootCAs.Clone()
+	} else if !config.ExcludeSystemCAs {
+		systemPool, err := x509.SystemCertPool()
+		if err == nil {
+			pool = systemPool
+		}
+	}
+	if config.CACertFile != "" {
+		pem, err := ioutil.ReadFile(config.CACertFile)
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("No CA certificate found in " + config.CACertFile)
+		}
+	}
+	if len(config.CACerts) > 0 && !pool.AppendCertsFromPEM(config.CACerts) {
+		return nil, errors.New("No CA certificate found in CACerts")
+	}
+	tlsConfig.RootCAs = pool
+	return tlsConfig, nil
+}
+
+// certificateReloader loads a client certificate from its files, reloading it whenever one of the files changes,
+// so that rotated certificates are picked up by the next TLS handshake
+type certificateReloader struct {
+	certFile, keyFile string
+
+	mu          sync.Mutex
+	certificate *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+}
+
+func newCertificateReloader(certFile, keyFile string) (*certificateReloader, error) {
+	reloader := &certificateReloader{certFile: certFile, keyFile: keyFile}
+	if _, err := reloader.GetClientCertificate(nil); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+// GetClientCertificate returns the certificate of the files, reloading them if they changed since last loaded.
+// The previous certificate is kept if the files cannot be loaded, like when caught in the middle of a rotation.
+func (reloader *certificateReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	reloader.mu.Lock()
+	defer reloader.mu.Unlock()
+
+	certificate, err := reloader.reload()
+	if err == nil {
+		return certificate, nil
+	}
+	if reloader.certificate != nil {
+		return reloader.certificate, nil
+	}
+	return nil, fmt.Errorf("Could not load the client certificate: %v", err)
+}
+
+func (reloader *certificateReloader) reload() (*tls.Certificate, error) {
+	certInfo, err := os.Stat(reloader.certFile)
+	if err != nil {
+		return nil, err
+	}
+	keyInfo, err := os.Stat(reloader.keyFile)
+	if err != nil {
+		return nil, err
+	}
+	if reloader.certificate != nil && certInfo.ModTime().Equal(reloader.certModTime) && keyInfo.ModTime().Equal(reloader.keyModTime) {
+		return reloader.certificate, nil
+	}
+
+	certificate, err := tls.LoadX509KeyPair(reloader.certFile, reloader.keyFile)
+	if err != nil {
+		return nil, err
+	}
+	reloader.certificate = &certificate
+	reloader.certModTime, reloader.keyModTime = certInfo.ModTime(), keyInfo.ModTime()
+	return reloader.certificate, nil
+}
@@ -0,0 +1,327 @@
+package autoscaler_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/bijukunjummen/app-autoscaler-client"
+	"github.com/onsi/gomega/ghttp"
+)
+
+// clientCertificate generates a self-signed client certificate, returning its PEM encoded certificate and key
+func clientCertificate(commonName string) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Ω(err).Should(BeNil())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Ω(err).Should(BeNil())
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Ω(err).Should(BeNil())
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+var _ = Describe("Transport", func() {
+	var server *ghttp.Server
+	var config *Config
+	var caPEM []byte
+
+	BeforeEach(func() {
+		server = ghttp.NewUnstartedServer()
+		server.HTTPTestServer.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+		server.HTTPTestServer.StartTLS()
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v2/info"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, Endpoint{
+					AuthorizationEndpoint: server.URL(),
+					TokenEndpoint:         server.URL(),
+				}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/oauth/token"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, AccessToken{
+					Token: "test-token",
+				}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/bindings/binding-1"),
+				ghttp.RespondWith(http.StatusOK, `{"guid": "binding-1"}`),
+			),
+		)
+		caPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.HTTPTestServer.Certificate().Raw})
+		config = &Config{
+			CFConfig: &CFConfig{
+				CCApiURL: server.URL(),
+				Username: "user",
+				Password: "pwd",
+			},
+			AutoscalerAPIUrl: server.URL() + "/api",
+			InstanceGUID:     "instanceid",
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("Given CA certificates", func() {
+		It("Should trust the CA certificates for every call", func() {
+			config.CFConfig.CACerts = caPEM
+
+			client, err := NewClient(config)
+			Ω(err).Should(BeNil())
+			_, err = client.GetBinding("binding-1")
+
+			Ω(err).Should(BeNil())
+			Ω(len(server.ReceivedRequests())).Should(Equal(3))
+		})
+
+		It("Should read the CA certificates from a file", func() {
+			dir, err := ioutil.TempDir("", "ca")
+			Ω(err).Should(BeNil())
+			defer os.RemoveAll(dir)
+			config.CFConfig.CACertFile = filepath.Join(dir, "ca.pem")
+			Ω(ioutil.WriteFile(config.CFConfig.CACertFile, caPEM, 0600)).Should(Succeed())
+			config.CFConfig.ExcludeSystemCAs = true
+
+			_, err = NewClient(config)
+
+			Ω(err).Should(BeNil())
+		})
+
+		It("Should reject invalid CA certificates", func() {
+			config.CFConfig.CACerts = []byte("not a certificate")
+
+			_, err := NewClient(config)
+
+			Ω(err).Should(MatchError(ContainSubstring("No CA certificate found")))
+		})
+
+		It("Should not trust an unknown CA", func() {
+			_, err := NewClient(config)
+
+			Ω(err).Should(MatchError(ContainSubstring("certificate")))
+		})
+	})
+	Context("Given a client certificate", func() {
+		var dir string
+		var commonNames []string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "mtls")
+			Ω(err).Should(BeNil())
+			config.CFConfig.CACerts = caPEM
+			commonNames = nil
+			for i := 0; i < 3; i++ {
+				server.WrapHandler(i, func(w http.ResponseWriter, r *http.Request) {
+					Ω(r.TLS.PeerCertificates).ShouldNot(BeEmpty())
+					commonNames = append(commonNames, r.TLS.PeerCertificates[0].Subject.CommonName)
+				})
+			}
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("Should present the certificate for every call", func() {
+			config.CFConfig.ClientCert, config.CFConfig.ClientKey = clientCertificate("client")
+
+			client, err := NewClient(config)
+			Ω(err).Should(BeNil())
+			_, err = client.GetBinding("binding-1")
+
+			Ω(err).Should(BeNil())
+			Ω(commonNames).Should(Equal([]string{"client", "client", "client"}))
+		})
+
+		It("Should reload the certificate files once rotated", func() {
+			config.CFConfig.ClientCertFile = filepath.Join(dir, "cert.pem")
+			config.CFConfig.ClientKeyFile = filepath.Join(dir, "key.pem")
+			writeCertificate := func(commonName string, modTime time.Time) {
+				cert, key := clientCertificate(commonName)
+				Ω(ioutil.WriteFile(config.CFConfig.ClientCertFile, cert, 0600)).Should(Succeed())
+				Ω(ioutil.WriteFile(config.CFConfig.ClientKeyFile, key, 0600)).Should(Succeed())
+				Ω(os.Chtimes(config.CFConfig.ClientCertFile, modTime, modTime)).Should(Succeed())
+				Ω(os.Chtimes(config.CFConfig.ClientKeyFile, modTime, modTime)).Should(Succeed())
+			}
+			writeCertificate("before", time.Now().Add(-time.Minute))
+
+			client, err := NewClient(config)
+			Ω(err).Should(BeNil())
+			writeCertificate("after", time.Now())
+			server.HTTPTestServer.CloseClientConnections()
+			_, err = client.GetBinding("binding-1")
+
+			Ω(err).Should(BeNil())
+			Ω(commonNames).Should(Equal([]string{"before", "before", "after"}))
+		})
+
+		It("Should fail on missing certificate files", func() {
+			config.CFConfig.ClientCertFile = filepath.Join(dir, "missing.pem")
+			config.CFConfig.ClientKeyFile = filepath.Join(dir, "missing.key")
+
+			_, err := NewClient(config)
+
+			Ω(err).Should(MatchError(ContainSubstring("Could not load the client certificate")))
+		})
+	})
+
+	Context("Given a proxy", func() {
+		var proxy *ghttp.Server
+
+		BeforeEach(func() {
+			proxy = ghttp.NewServer()
+			proxy.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v2/info"),
+					func(w http.ResponseWriter, r *http.Request) {
+						Ω(r.Host).Should(Equal("api.example.invalid"))
+					},
+					ghttp.RespondWithJSONEncoded(http.StatusOK, Endpoint{
+						AuthorizationEndpoint: "http://uaa.example.invalid",
+						TokenEndpoint:         "http://uaa.example.invalid",
+					}),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/oauth/token"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, AccessToken{
+						Token: "test-token",
+					}),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/bindings/binding-1"),
+					ghttp.VerifyHeaderKV("Authorization", "Bearer test-token"),
+					ghttp.RespondWith(http.StatusOK, `{"guid": "binding-1"}`),
+				),
+			)
+			config.CFConfig.CCApiURL = "http://api.example.invalid"
+			config.AutoscalerAPIUrl = "http://autoscale.example.invalid/api"
+			config.CFConfig.ProxyURL = proxy.URL()
+		})
+
+		AfterEach(func() {
+			proxy.Close()
+		})
+
+		It("Should make every call through the proxy", func() {
+			client, err := NewClient(config)
+			Ω(err).Should(BeNil())
+			_, err = client.GetBinding("binding-1")
+
+			Ω(err).Should(BeNil())
+			Ω(len(proxy.ReceivedRequests())).Should(Equal(3))
+		})
+
+		It("Should not use the proxy for the hosts excluded", func() {
+			config.CFConfig.NoProxy = "localhost,.example.invalid"
+
+			_, err := NewClient(config)
+
+			Ω(err).ShouldNot(BeNil())
+			Ω(proxy.ReceivedRequests()).Should(BeEmpty())
+		})
+
+		It("Should use the proxy of the environment when only the hosts excluded are set", func() {
+			os.Setenv("HTTP_PROXY", proxy.URL())
+			defer os.Unsetenv("HTTP_PROXY")
+			config.CFConfig.ProxyURL = ""
+			config.CFConfig.NoProxy = "internal.example.com"
+
+			client, err := NewClient(config)
+			Ω(err).Should(BeNil())
+			_, err = client.GetBinding("binding-1")
+
+			Ω(err).Should(BeNil())
+			Ω(len(proxy.ReceivedRequests())).Should(Equal(3))
+		})
+	})
+
+	Context("Given a custom transport", func() {
+		It("Should make every call through the transport", func() {
+			var hosts []string
+			config.CFConfig.CACerts = caPEM
+			transport := &http.Transport{
+				Proxy: func(r *http.Request) (*url.URL, error) {
+					hosts = append(hosts, r.URL.Host)
+					return nil, nil
+				},
+			}
+			config.CFConfig.Transport = transport
+
+			client, err := NewClient(config)
+			Ω(err).Should(BeNil())
+			_, err = client.GetBinding("binding-1")
+
+			Ω(err).Should(BeNil())
+			Ω(hosts).Should(HaveLen(3))
+		})
+
+		It("Should keep the TLS settings of the transport", func() {
+			pool := x509.NewCertPool()
+			pool.AppendCertsFromPEM(caPEM)
+			transport := &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}}
+			config.CFConfig.Transport = transport
+			config.CFConfig.ClientCert, config.CFConfig.ClientKey = clientCertificate("client")
+
+			client, err := NewClient(config)
+			Ω(err).Should(BeNil())
+			_, err = client.GetBinding("binding-1")
+
+			Ω(err).Should(BeNil())
+			Ω(transport.TLSClientConfig.Certificates).Should(BeEmpty())
+
+			transport.TLSClientConfig.ServerName = "wrong.invalid"
+			_, err = NewClient(config)
+			Ω(err).Should(MatchError(ContainSubstring("wrong.invalid")))
+		})
+
+		It("Should use a custom RoundTripper as is", func() {
+			var calls int
+			config.CFConfig.Transport = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				calls++
+				return (&http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}).RoundTrip(r)
+			})
+
+			client, err := NewClient(config)
+			Ω(err).Should(BeNil())
+			_, err = client.GetBinding("binding-1")
+
+			Ω(err).Should(BeNil())
+			Ω(calls).Should(Equal(3))
+
+			config.CFConfig.ProxyURL = "http://proxy.example.invalid"
+			_, err = NewClient(config)
+			Ω(err).Should(MatchError(ContainSubstring("Cannot apply the TLS and proxy settings")))
+		})
+	})
+})
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return fn(r)
+}
@@ -22,7 +22,7 @@ type ScheduledLimitChange struct {
 	MinInstances       int        `json:"min_instances"`
 	MaxInstances       int        `json:"max_instances"`
 	ServiceBindingGUID string     `json:"service_binding_guid,omitempty"`
-	Recurrence         int        `json:"recurrence"`
+	Recurrence         Recurrence `json:"recurrence"`
 	Enabled            bool       `json:"enabled"`
 }
 
@@ -33,6 +33,7 @@ type Rule struct {
 	CreatedAt          *time.Time `json:"created_at,omitempty"`
 	UpdatedAt          *time.Time `json:"updated_at,omitempty"`
 	Type               string     `json:"type"`
+	SubType            string     `json:"sub_type,omitempty"`
 	Enabled            bool       `json:"enabled"`
 	MinThreshold       int        `json:"min_threshold"`
 	MaxThreshold       int        `json:"max_threshold"`
@@ -73,6 +74,28 @@ type ScheduledLimitChangesResource struct {
 //ScalingDecisionsResource ...
 type ScalingDecisionsResource struct {
 	ScalingDecisions []ScalingDecision `json:"resources"`
+	Pagination       *Pagination       `json:"pagination,omitempty"`
+	NextURL          string            `json:"next_url,omitempty"`
+}
+
+// Pagination - paging information returned along with a list of resources
+type Pagination struct {
+	TotalResults int   `json:"total_results,omitempty"`
+	TotalPages   int   `json:"total_pages,omitempty"`
+	First        *Link `json:"first,omitempty"`
+	Last         *Link `json:"last,omitempty"`
+	Next         *Link `json:"next,omitempty"`
+	Previous     *Link `json:"previous,omitempty"`
+}
+
+// ScalingDecisionsFilter narrows down the Scaling decisions returned for a Binding
+type ScalingDecisionsFilter struct {
+	// Only decisions created at or after Since are returned, if set
+	Since time.Time
+	// Only decisions created at or before Until are returned, if set
+	Until time.Time
+	// At most MaxResults of the most recent decisions are returned, if greater than 0
+	MaxResults int
 }
 
 // Link -
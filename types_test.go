@@ -134,7 +134,7 @@ var _ = Describe("ServiceInstances type", func() {
 				Ω(nsl.MinInstances).Should(Equal(2))
 				Ω(nsl.MaxInstances).Should(Equal(5))
 				Ω(nsl.ServiceBindingGUID).Should(Equal("540f43bc-b9cc-4126-97a4-a56b64052da4"))
-				Ω(nsl.Recurrence).Should(Equal(1))
+				Ω(nsl.Recurrence).Should(Equal(Recurrence(1)))
 				Ω(nsl.Enabled).Should(Equal(true))
 				ea, _ := time.Parse(time.RFC3339, "2021-01-01T00:00:00Z")
 				Ω(*nsl.ExecutesAt).Should(Equal(ea))
@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"net/http"
 
-	"crypto/tls"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"io"
+	"strings"
+	"time"
 
 	"golang.org/x/net/context"
 	"golang.org/x/oauth2"
@@ -37,14 +39,49 @@ type CFConfig struct {
 	ClientID          string
 	ClientSecret      string
 	SkipSslValidation bool
-	httpClient        *http.Client
-	TokenSource       oauth2.TokenSource
+	// AccessToken and RefreshToken of a previous login, like the one of the cf CLI, are used instead of the
+	// credentials when set. The token is refreshed through UAA as the ClientID, "cf" if not set, once expired.
+	AccessToken  string
+	RefreshToken string
+	// BearerToken is a token obtained elsewhere, like from a vault sidecar, sent as is on every call without going
+	// through UAA. It is never refreshed, the caller building a new client once it expires.
+	BearerToken string
+	// SkipInfoValidation skips the /v2/info call checking Cloud Controller is reachable when authenticating with
+	// a BearerToken or a TokenSource, which need nothing from it. It is ignored for the other ways to authenticate.
+	SkipInfoValidation bool
+	// CACertFile is the path to a PEM bundle of CA certificates to trust, and CACerts PEM encoded CA certificates
+	// to trust, for Cloud Controller, UAA and the Autoscaler API. They are added to the RootCAs of the Transport
+	// if set, else to the system ones unless ExcludeSystemCAs is set.
+	CACertFile       string
+	CACerts          []byte
+	ExcludeSystemCAs bool
+	// ClientCertFile and ClientKeyFile are the PEM files of the certificate presented to servers asking for mutual
+	// TLS, reloaded whenever they change. ClientCert and ClientKey are PEM encoded alternatives to the files.
+	ClientCertFile string
+	ClientKeyFile  string
+	ClientCert     []byte
+	ClientKey      []byte
+	// Transport is the base of every call, under the oauth2 layer, like an *http.Transport with its own dialer,
+	// timeouts or connection pool. The default transport is used if not set. The TLS settings of the config are
+	// added to a copy of its TLSClientConfig, keeping the rest of it.
+	Transport http.RoundTripper
+	// ProxyURL is the proxy every call goes through but the ones to NoProxy, a comma separated list of hosts and
+	// domains like in NO_PROXY. The proxy of the environment is used if ProxyURL is not set, with NoProxy
+	// replacing NO_PROXY if set.
+	ProxyURL   string
+	NoProxy    string
+	httpClient *http.Client
+	// TokenSource supplies the tokens of every call when no credentials, AccessToken, RefreshToken or BearerToken
+	// are set, letting the caller handle the authentication. It is set to the token source built from them otherwise.
+	TokenSource oauth2.TokenSource
 }
 
 // OauthHTTPWrapper is an http client wrapper that makes the call with an oauth2 token
 type OauthHTTPWrapper interface {
 	NewCCRequest(method, path string, body io.Reader) (*http.Request, error)
+	NewCCRequestWithContext(ctx context.Context, method, path string, body io.Reader) (*http.Request, error)
 	NewRequest(method, url string, body io.Reader) (*http.Request, error)
+	NewRequestWithContext(ctx context.Context, method, url string, body io.Reader) (*http.Request, error)
 	Do(request *http.Request) (*http.Response, error)
 }
 
@@ -67,29 +104,38 @@ func DefaultCFConfig() *CFConfig {
 
 // NewUAAClient - Creates a new UAA Client
 func NewUAAClient(config *CFConfig) (OauthHTTPWrapper, error) {
-	ctx := context.Background()
-	defConfig := DefaultCFConfig()
-
-	if !config.SkipSslValidation {
-		ctx = context.WithValue(ctx, oauth2.HTTPClient, defConfig.httpClient)
-	} else {
-		tr := &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		}
-		ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: tr})
-	}
-
-	endpoint, err := getInfo(config.CCApiURL, oauth2.NewClient(ctx, nil))
+	return NewUAAClientWithContext(context.Background(), config)
+}
 
+// NewUAAClientWithContext - Creates a new UAA Client, the context applies to the /v2/info and the initial token calls.
+// Tokens refreshed later on are not bound to the context.
+func NewUAAClientWithContext(ctx context.Context, config *CFConfig) (OauthHTTPWrapper, error) {
+	transport, err := newTransport(config)
 	if err != nil {
-		return nil, fmt.Errorf("Could not get api /v2/info: %v", err)
+		return nil, err
+	}
+	httpClient := &http.Client{Transport: transport}
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+	tokenCtx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)
+
+	external := config.BearerToken != "" || (config.TokenSource != nil && !hasCredentials(config))
+	var endpoint *Endpoint
+	if !external || !config.SkipInfoValidation {
+		endpoint, err = getInfo(ctx, config.CCApiURL, oauth2.NewClient(ctx, nil))
+		if err != nil {
+			return nil, fmt.Errorf("Could not get api /v2/info: %v", err)
+		}
 	}
 
 	switch {
+	case external:
+		config = getExternalAuth(tokenCtx, config)
+	case config.AccessToken != "" || config.RefreshToken != "":
+		config = getTokenAuth(tokenCtx, config, endpoint)
 	case config.ClientID != "":
-		config = getClientAuth(config, endpoint, ctx)
+		config = getClientAuth(config, endpoint, tokenCtx)
 	default:
-		config, err = getUserAuth(config, endpoint, ctx)
+		config, err = getUserAuth(ctx, tokenCtx, config, endpoint)
 		if err != nil {
 			return nil, err
 		}
@@ -109,15 +155,30 @@ func (client *TokenHandlingClient) Do(request *http.Request) (*http.Response, er
 
 // NewCCRequest ...
 func (client *TokenHandlingClient) NewCCRequest(method, path string, body io.Reader) (*http.Request, error) {
-	return http.NewRequest(method, fmt.Sprintf("%s%s", client.Config.CCApiURL, path), body)
+	return client.NewCCRequestWithContext(context.Background(), method, path, body)
+}
+
+// NewCCRequestWithContext ...
+func (client *TokenHandlingClient) NewCCRequestWithContext(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	return client.NewRequestWithContext(ctx, method, fmt.Sprintf("%s%s", client.Config.CCApiURL, path), body)
 }
 
 // NewRequest ...
 func (client *TokenHandlingClient) NewRequest(method, url string, body io.Reader) (*http.Request, error) {
-	return http.NewRequest(method, url, body)
+	return client.NewRequestWithContext(context.Background(), method, url, body)
+}
+
+// NewRequestWithContext ...
+func (client *TokenHandlingClient) NewRequestWithContext(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	request, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	return request.WithContext(ctx), nil
 }
 
-func getUserAuth(config *CFConfig, endpoint *Endpoint, ctx context.Context) (*CFConfig, error) {
+// getUserAuth fetches the initial token using ctx, tokenCtx is retained by the token source for later refreshes
+func getUserAuth(ctx context.Context, tokenCtx context.Context, config *CFConfig, endpoint *Endpoint) (*CFConfig, error) {
 	authConfig := &oauth2.Config{
 		ClientID: "cf",
 		Scopes:   []string{""},
@@ -133,10 +194,82 @@ func getUserAuth(config *CFConfig, endpoint *Endpoint, ctx context.Context) (*CF
 		return nil, fmt.Errorf("Error getting token: %v", err)
 	}
 
+	config.TokenSource = authConfig.TokenSource(tokenCtx, token)
+	config.httpClient = oauth2.NewClient(tokenCtx, config.TokenSource)
+
+	return config, err
+}
+
+// hasCredentials returns true if the config sets any credentials to authenticate with through UAA
+func hasCredentials(config *CFConfig) bool {
+	return config.Username != "" || config.ClientID != "" || config.AccessToken != "" || config.RefreshToken != ""
+}
+
+// getExternalAuth sends the bearer token of the config, or the tokens of its token source, without going through UAA
+func getExternalAuth(ctx context.Context, config *CFConfig) *CFConfig {
+	if config.BearerToken != "" {
+		config.TokenSource = oauth2.StaticTokenSource(&oauth2.Token{
+			AccessToken: trimBearer(config.BearerToken),
+			TokenType:   "bearer",
+		})
+	}
+	config.httpClient = oauth2.NewClient(ctx, config.TokenSource)
+	return config
+}
+
+// getTokenAuth uses the tokens of a previous login, refreshing the access token through UAA once expired
+func getTokenAuth(ctx context.Context, config *CFConfig, endpoint *Endpoint) *CFConfig {
+	clientID := config.ClientID
+	if clientID == "" {
+		clientID = "cf"
+	}
+	authConfig := &oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: config.ClientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  endpoint.AuthorizationEndpoint + "/oauth/auth",
+			TokenURL: endpoint.TokenEndpoint + "/oauth/token",
+		},
+	}
+
+	token := &oauth2.Token{
+		AccessToken:  trimBearer(config.AccessToken),
+		RefreshToken: config.RefreshToken,
+		TokenType:    "bearer",
+		Expiry:       tokenExpiry(config.AccessToken),
+	}
+	if token.Expiry.IsZero() && token.RefreshToken != "" {
+		// the expiry of the access token is unknown, refresh it right away rather than never
+		token.Expiry = time.Now()
+	}
+
 	config.TokenSource = authConfig.TokenSource(ctx, token)
 	config.httpClient = oauth2.NewClient(ctx, config.TokenSource)
+	return config
+}
 
-	return config, err
+// trimBearer strips the token type from a token written like in an Authorization header
+func trimBearer(token string) string {
+	return strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(token, "bearer "), "Bearer "))
+}
+
+// tokenExpiry returns the expiry of a JWT access token, zero if it cannot be told
+func tokenExpiry(accessToken string) time.Time {
+	parts := strings.Split(accessToken, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err = json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
 }
 
 func getClientAuth(config *CFConfig, endpoint *Endpoint, ctx context.Context) *CFConfig {
@@ -151,14 +284,18 @@ func getClientAuth(config *CFConfig, endpoint *Endpoint, ctx context.Context) *C
 	return config
 }
 
-func getInfo(api string, httpClient *http.Client) (*Endpoint, error) {
+func getInfo(ctx context.Context, api string, httpClient *http.Client) (*Endpoint, error) {
 	var endpoint Endpoint
 
 	if api == "" {
 		return nil, errors.New("Missing CC API url")
 	}
 
-	resp, err := httpClient.Get(api + "/v2/info")
+	request, err := http.NewRequest("GET", api+"/v2/info", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(request.WithContext(ctx))
 	if err != nil {
 		return nil, err
 	}
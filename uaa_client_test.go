@@ -8,6 +8,8 @@ import (
 
 	. "github.com/bijukunjummen/app-autoscaler-client"
 	"github.com/onsi/gomega/ghttp"
+	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
 )
 
 var _ = Describe("UAA Client", func() {
@@ -70,5 +72,103 @@ var _ = Describe("UAA Client", func() {
 
 		})
 
+		It("Should make the calls with a context", func() {
+
+			client, err := NewUAAClientWithContext(context.Background(), &config)
+
+			Ω(err).Should(BeNil())
+
+			request, err := client.NewCCRequestWithContext(context.Background(), "GET", "/v2/organizations", nil)
+			Ω(err).Should(BeNil())
+
+			_, err = client.Do(request)
+
+			Ω(err).Should(BeNil())
+		})
+
+		It("Should fail to get a token with a cancelled context", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, err := NewUAAClientWithContext(ctx, &config)
+
+			Ω(err).ShouldNot(BeNil())
+			Ω(server.ReceivedRequests()).Should(BeEmpty())
+		})
+
+	})
+	Context("Given a token obtained elsewhere", func() {
+		var server *ghttp.Server
+
+		BeforeEach(func() {
+			server = ghttp.NewServer()
+			server.RouteToHandler("GET", "/v2/info", ghttp.RespondWithJSONEncoded(http.StatusOK, Endpoint{
+				AuthorizationEndpoint: server.URL(),
+				TokenEndpoint:         server.URL(),
+			}))
+			server.RouteToHandler("GET", "/v2/organizations", ghttp.VerifyHeaderKV("Authorization", "Bearer vault-token"))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		get := func(config *CFConfig) {
+			client, err := NewUAAClient(config)
+			Ω(err).Should(BeNil())
+			request, err := client.NewCCRequest("GET", "/v2/organizations", nil)
+			Ω(err).Should(BeNil())
+
+			resp, err := client.Do(request)
+
+			Ω(err).Should(BeNil())
+			Ω(resp.StatusCode).Should(Equal(http.StatusOK))
+		}
+
+		It("Should send the bearer token without going through UAA", func() {
+			get(&CFConfig{CCApiURL: server.URL(), BearerToken: "bearer vault-token"})
+
+			Ω(server.ReceivedRequests()).Should(HaveLen(2))
+			Ω(server.ReceivedRequests()[0].URL.Path).Should(Equal("/v2/info"))
+		})
+
+		It("Should use the token source of the caller", func() {
+			tokenSource := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "vault-token"})
+
+			get(&CFConfig{CCApiURL: server.URL(), TokenSource: tokenSource})
+
+			Ω(server.ReceivedRequests()).Should(HaveLen(2))
+		})
+
+		It("Should skip the /v2/info call if told so", func() {
+			get(&CFConfig{CCApiURL: server.URL(), BearerToken: "vault-token", SkipInfoValidation: true})
+
+			Ω(server.ReceivedRequests()).Should(HaveLen(1))
+		})
+
+		It("Should fail when Cloud Controller cannot be reached", func() {
+			_, err := NewUAAClient(&CFConfig{CCApiURL: "http://127.0.0.1:1", BearerToken: "vault-token"})
+
+			Ω(err).Should(MatchError(ContainSubstring("Could not get api /v2/info")))
+		})
+
+		It("Should not need Cloud Controller for the Autoscaler API if told so", func() {
+			server.RouteToHandler("GET", "/api/bindings/binding-1", ghttp.CombineHandlers(
+				ghttp.VerifyHeaderKV("Authorization", "Bearer vault-token"),
+				ghttp.RespondWith(http.StatusOK, `{"guid": "binding-1"}`),
+			))
+			client, err := NewClient(&Config{
+				CFConfig:         &CFConfig{BearerToken: "vault-token", SkipInfoValidation: true},
+				AutoscalerAPIUrl: server.URL() + "/api",
+				InstanceGUID:     "instance-1",
+			})
+			Ω(err).Should(BeNil())
+
+			binding, err := client.GetBinding("binding-1")
+
+			Ω(err).Should(BeNil())
+			Ω(binding.GUID).Should(Equal("binding-1"))
+			Ω(server.ReceivedRequests()).Should(HaveLen(1))
+		})
 	})
 })
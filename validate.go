@@ -0,0 +1,116 @@
+package autoscaler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FieldError describes why the value of a field is invalid, the field being named after its JSON name
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (fieldError FieldError) String() string {
+	return fmt.Sprintf("%s %s", fieldError.Field, fieldError.Message)
+}
+
+// ValidationError is returned when a resource fails validation, listing every invalid field
+type ValidationError struct {
+	// Resource is the kind of resource validated, like "binding", "rule" or "scheduled limit change"
+	Resource string
+	Fields   []FieldError
+}
+
+func (validationError *ValidationError) Error() string {
+	var fields []string
+	for _, field := range validationError.Fields {
+		fields = append(fields, field.String())
+	}
+	return fmt.Sprintf("Invalid %s: %s", validationError.Resource, strings.Join(fields, ", "))
+}
+
+// IsValidationError returns true if the error is a ValidationError
+func IsValidationError(err error) bool {
+	var validationError *ValidationError
+	return errors.As(err, &validationError)
+}
+
+// percentRuleTypes are the rule types with thresholds given in percent
+var percentRuleTypes = map[string]bool{
+	"cpu":    true,
+	"memory": true,
+}
+
+// validator collects the invalid fields of a resource
+type validator struct {
+	resource string
+	fields   []FieldError
+}
+
+func (v *validator) check(valid bool, field, format string, args ...interface{}) {
+	if !valid {
+		v.fields = append(v.fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Resource: v.resource, Fields: v.fields}
+}
+
+// Validate checks the instance limits of the Binding. Zero limits are not sent to the API and left unchecked.
+func (binding *Binding) Validate() error {
+	v := &validator{resource: "binding"}
+	v.check(binding.MinInstances >= 0, "min_instances", "must not be negative")
+	v.check(binding.MaxInstances >= 0, "max_instances", "must not be negative")
+	v.check(binding.ExpectedInstanceCount >= 0, "expected_instance_count", "must not be negative")
+	v.check(binding.MinInstances <= 0 || binding.MaxInstances <= 0 || binding.MinInstances <= binding.MaxInstances,
+		"min_instances", "must not be greater than max_instances %d", binding.MaxInstances)
+	return v.err()
+}
+
+// Validate checks the type and thresholds of the Rule, thresholds of cpu and memory rules being percentages
+func (rule *Rule) Validate() error {
+	v := &validator{resource: "rule"}
+	v.check(rule.Type != "", "type", "is required")
+	v.check(rule.MinThreshold >= 0, "min_threshold", "must not be negative")
+	v.check(rule.MaxThreshold >= 0, "max_threshold", "must not be negative")
+	if percentRuleTypes[rule.Type] {
+		v.check(rule.MinThreshold <= 100, "min_threshold", "must be a percentage between 0 and 100")
+		v.check(rule.MaxThreshold <= 100, "max_threshold", "must be a percentage between 0 and 100")
+	}
+	v.check(rule.MinThreshold <= rule.MaxThreshold,
+		"min_threshold", "must not be greater than max_threshold %d", rule.MaxThreshold)
+	return v.err()
+}
+
+// Validate checks the instance limits, recurrence and execution time of the ScheduledLimitChange,
+// which must be in the future for a change executing once. A recurring change keeps the time it first executed at.
+func (scheduledLimitChange *ScheduledLimitChange) Validate() error {
+	v := &validator{resource: "scheduled limit change"}
+	v.check(scheduledLimitChange.MinInstances >= 0, "min_instances", "must not be negative")
+	v.check(scheduledLimitChange.MaxInstances > 0, "max_instances", "must be positive")
+	v.check(scheduledLimitChange.MinInstances <= scheduledLimitChange.MaxInstances,
+		"min_instances", "must not be greater than max_instances %d", scheduledLimitChange.MaxInstances)
+	v.check(scheduledLimitChange.Recurrence.Valid(), "recurrence", "must be a bitmask between 0 and %d", EveryDay)
+	if scheduledLimitChange.ExecutesAt == nil {
+		v.check(false, "executes_at", "is required")
+	} else if scheduledLimitChange.Recurrence == 0 {
+		v.check(scheduledLimitChange.ExecutesAt.After(time.Now()), "executes_at",
+			"must be in the future, got %s", scheduledLimitChange.ExecutesAt.Format(time.RFC3339))
+	}
+	return v.err()
+}
+
+// validate validates the resource before it is sent when the client is in strict mode
+func (client *DefaultClient) validate(resource interface{ Validate() error }) error {
+	if !client.config.StrictValidation {
+		return nil
+	}
+	return resource.Validate()
+}
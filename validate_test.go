@@ -0,0 +1,138 @@
+package autoscaler_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"net/http"
+	"time"
+
+	. "github.com/bijukunjummen/app-autoscaler-client"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Validation", func() {
+	fields := func(err error) []string {
+		Ω(IsValidationError(err)).Should(BeTrue())
+		var names []string
+		for _, field := range err.(*ValidationError).Fields {
+			names = append(names, field.Field)
+		}
+		return names
+	}
+
+	It("Should validate the limits of a binding", func() {
+		Ω((&Binding{MinInstances: 2, MaxInstances: 5}).Validate()).Should(Succeed())
+		Ω((&Binding{Enabled: true}).Validate()).Should(Succeed())
+
+		err := (&Binding{MinInstances: 6, MaxInstances: 5, ExpectedInstanceCount: -1}).Validate()
+		Ω(fields(err)).Should(Equal([]string{"expected_instance_count", "min_instances"}))
+		Ω(err.Error()).Should(Equal("Invalid binding: expected_instance_count must not be negative, " +
+			"min_instances must not be greater than max_instances 5"))
+	})
+
+	It("Should validate the thresholds of a rule", func() {
+		Ω((&Rule{Type: "cpu", MinThreshold: 20, MaxThreshold: 80}).Validate()).Should(Succeed())
+		Ω((&Rule{Type: "http_latency", SubType: "avg_99th", MinThreshold: 200, MaxThreshold: 1000}).Validate()).Should(Succeed())
+
+		Ω(fields((&Rule{Type: "memory", MinThreshold: 20, MaxThreshold: 120}).Validate())).Should(Equal([]string{"max_threshold"}))
+		Ω(fields((&Rule{MinThreshold: -1, MaxThreshold: 10}).Validate())).Should(Equal([]string{"type", "min_threshold"}))
+		Ω(fields((&Rule{Type: "cpu", MinThreshold: 80, MaxThreshold: 20}).Validate())).Should(Equal([]string{"min_threshold"}))
+	})
+
+	It("Should validate a scheduled limit change", func() {
+		future := time.Now().Add(time.Hour)
+		past := time.Now().Add(-time.Hour)
+
+		Ω((&ScheduledLimitChange{ExecutesAt: &future, MinInstances: 1, MaxInstances: 4}).Validate()).Should(Succeed())
+		Ω((&ScheduledLimitChange{ExecutesAt: &past, MinInstances: 1, MaxInstances: 4, Recurrence: Weekdays}).Validate()).Should(Succeed())
+
+		Ω(fields((&ScheduledLimitChange{ExecutesAt: &past, MinInstances: 1, MaxInstances: 4}).Validate())).
+			Should(Equal([]string{"executes_at"}))
+		Ω(fields((&ScheduledLimitChange{MinInstances: -1, Recurrence: 128}).Validate())).
+			Should(Equal([]string{"min_instances", "max_instances", "recurrence", "executes_at"}))
+	})
+
+	Context("Given a client in strict mode", func() {
+		var server *ghttp.Server
+		var client Client
+
+		BeforeEach(func() {
+			server = ghttp.NewServer()
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v2/info"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, Endpoint{
+						AuthorizationEndpoint: server.URL(),
+						TokenEndpoint:         server.URL(),
+					}),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/oauth/token"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, AccessToken{
+						Token: "test-token",
+					}),
+				),
+			)
+			var err error
+			client, err = NewClient(&Config{
+				CFConfig: &CFConfig{
+					CCApiURL:          server.URL(),
+					Username:          "user",
+					Password:          "pwd",
+					SkipSslValidation: true,
+				},
+				AutoscalerAPIUrl: server.URL() + "/api",
+				InstanceGUID:     "instanceid",
+				StrictValidation: true,
+			})
+			Ω(err).Should(BeNil())
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("Should refuse invalid payloads without calling the API", func() {
+			_, err := client.UpdateBinding("binding-1", &Binding{MinInstances: 6, MaxInstances: 5})
+			Ω(IsValidationError(err)).Should(BeTrue())
+
+			_, err = client.CreateRule("binding-1", &Rule{Type: "cpu", MinThreshold: 20, MaxThreshold: 200})
+			Ω(IsValidationError(err)).Should(BeTrue())
+
+			_, err = client.UpdateScheduledLimitChange("binding-1", "schedule-1", &ScheduledLimitChange{MinInstances: 1, MaxInstances: 2})
+			Ω(IsValidationError(err)).Should(BeTrue())
+
+			Ω(len(server.ReceivedRequests())).Should(Equal(2))
+		})
+
+		It("Should send valid payloads", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/api/bindings/binding-1/rules"),
+					ghttp.RespondWith(http.StatusCreated, `{"guid": "rule-1", "type": "cpu"}`),
+				),
+			)
+
+			rule, err := client.CreateRule("binding-1", &Rule{Type: "cpu", MinThreshold: 20, MaxThreshold: 80})
+
+			Ω(err).Should(BeNil())
+			Ω(rule.GUID).Should(Equal("rule-1"))
+		})
+
+		It("Should update a recurring change which first executed in the past", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/api/bindings/binding-1/scheduled_limit_changes/schedule-1"),
+					ghttp.RespondWith(http.StatusOK, `{"guid": "schedule-1"}`),
+				),
+			)
+			executesAt := time.Date(2017, 1, 2, 9, 0, 0, 0, time.UTC)
+
+			_, err := client.UpdateScheduledLimitChange("binding-1", "schedule-1",
+				&ScheduledLimitChange{ExecutesAt: &executesAt, Recurrence: Weekdays, MinInstances: 4, MaxInstances: 10})
+
+			Ω(err).Should(BeNil())
+		})
+	})
+})